@@ -29,14 +29,38 @@ func (a Angle) Degrees() float64 {
 	return a.float64 * Radian
 }
 
-// NewAngleFromDegrees создаёт новый угол, заданный градусами.
+// NewAngleFromDegrees создаёт новый угол, заданный градусами, минутами и секундами; угол хранится в радианах,
+// как и у остальных конструкторов. Знак отрицательного угла относится и к минутам, и к секундам: -16°42' это -16.7°.
+// Угол от -1° до 0° задаётся отрицательными минутами или секундами: NewAngleFromDegrees(0, -30, 0) это -0.5°.
 func NewAngleFromDegrees(degree int, minutes, seconds float64) Angle {
-	return newAngle(float64(degree) + minutes/60 + seconds/3600)
+	angle := math.Abs(float64(degree)) + minutes/60 + seconds/3600
+	if degree < 0 {
+		angle = -angle
+	}
+	return newAngle(angle * Degree)
 }
 
-// NewClockAngle создаёт новый угол, заданный часами, минутами и секундами.
+// NewClockAngle создаёт новый угол, заданный часами, минутами и секундами (1 час — 15°); угол хранится в радианах.
 func NewClockAngle(hours uint, minutes, seconds float64) Angle {
-	return newAngle(15 * (float64(hours) + minutes/60 + seconds/3600))
+	return newAngle(15 * (float64(hours) + minutes/60 + seconds/3600) * Degree)
+}
+
+// Hours возвращает угол в часах: 24 часа — полный оборот.
+func (a Angle) Hours() float64 {
+	return a.float64 * Radian / 15
+}
+
+// Clock возвращает угол от 0 до 24 часов в часах, минутах и секундах, обратное преобразование к NewClockAngle.
+func (a Angle) Clock() (hours uint, minutes uint, seconds float64) {
+	value := math.Mod(a.Hours(), 24)
+	if value < 0 {
+		value += 24
+	}
+	hours = uint(value)
+	value = (value - float64(hours)) * 60
+	minutes = uint(value)
+	seconds = (value - float64(minutes)) * 60
+	return hours, minutes, seconds
 }
 
 func newAngle(angle float64) Angle {
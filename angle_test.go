@@ -0,0 +1,55 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewAngleFromDegrees(t *testing.T) {
+	tests := []struct {
+		degree           int
+		minutes, seconds float64
+		want             float64 // градусы
+	}{
+		{90, 0, 0, 90},
+		{23, 26, 21.448, 23.439291111111111},
+		{-16, 42, 0, -16.7},
+		{0, -30, 0, -0.5},
+		{-1, 30, 36, -1.51},
+	}
+	for _, test := range tests {
+		angle := NewAngleFromDegrees(test.degree, test.minutes, test.seconds)
+		if math.Abs(angle.Degrees()-test.want) > 1e-12 || math.Abs(angle.Radians()-test.want*Degree) > 1e-14 {
+			t.Errorf("%d°%v′%v″: %v°, want %v°", test.degree, test.minutes, test.seconds, angle.Degrees(), test.want)
+		}
+		if math.Abs(angle.Sin-math.Sin(test.want*Degree)) > 1e-15 {
+			t.Errorf("%d°%v′%v″: sin %v, want %v", test.degree, test.minutes, test.seconds, angle.Sin, math.Sin(test.want*Degree))
+		}
+	}
+}
+
+func TestNewClockAngle(t *testing.T) {
+	tests := []struct {
+		hours            uint
+		minutes, seconds float64
+		want             float64 // градусы
+	}{
+		{0, 0, 0, 0},
+		{6, 0, 0, 90},
+		{2, 44, 11.986, 41.049941666666667},
+		{23, 59, 59.999, 359.99999583333333},
+	}
+	for _, test := range tests {
+		angle := NewClockAngle(test.hours, test.minutes, test.seconds)
+		if math.Abs(angle.Degrees()-test.want) > 1e-9 {
+			t.Errorf("%dh%vm%vs: %v°, want %v°", test.hours, test.minutes, test.seconds, angle.Degrees(), test.want)
+		}
+		hours, minutes, seconds := angle.Clock()
+		if hours != test.hours || minutes != uint(test.minutes) || math.Abs(seconds-test.seconds) > 1e-6 {
+			t.Errorf("%dh%vm%vs: Clock %dh%dm%vs", test.hours, test.minutes, test.seconds, hours, minutes, seconds)
+		}
+		if math.Abs(angle.Hours()-test.want/15) > 1e-12 {
+			t.Errorf("%dh%vm%vs: Hours %v, want %v", test.hours, test.minutes, test.seconds, angle.Hours(), test.want/15)
+		}
+	}
+}
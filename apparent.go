@@ -0,0 +1,126 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import "math"
+
+// Видимое место звезды: отклонение света Солнцем, годичная аберрация, прецессия и нутация,
+// в том же порядке, что и в функции iauAtciqz библиотеки SOFA. Движение Земли вычисляется по упрощённой теории
+// Солнца из Astronomical Almanac, поэтому точность видимого места — около 0.1″.
+// Координаты каталогов J2000 считаются координатами ICRS: смещение начала отсчёта (около 0.02″) не учитывается.
+
+const (
+	// SpeedOfLight скорость света в астрономических единицах в сутки.
+	SpeedOfLight = 173.1446326846693
+	// sunSchwarzschildRadius гравитационный радиус Солнца в астрономических единицах.
+	sunSchwarzschildRadius = 1.97412574336e-8
+)
+
+// GetApparent возвращает видимое место звезды на эпоху epoch — координаты, отнесённые к истинному экватору
+// и равноденствию эпохи, для координат каталога, отнесённых к J2000. Собственное движение и параллакс не учитываются.
+func (c SphericalCoords) GetApparent(epoch Epoch) SphericalCoords {
+	coords := getVectorCoords(getObservedDirection(getUnitVector(c), epoch), c.Radius)
+	coords = coords.GetPrecessed(J2000, epoch)
+	return coords.GetNutated(epoch)
+}
+
+// getCatalogueFromApparent возвращает координаты каталога J2000 для видимого места c на эпоху epoch;
+// обратное преобразование к GetApparent.
+func getCatalogueFromApparent(c SphericalCoords, epoch Epoch) SphericalCoords {
+	coords := getMeanFromTrue(c, epoch).GetPrecessed(epoch, J2000)
+	// отклонение света и аберрация обращаются последовательными приближениями:
+	// поправка меньше 21″, и каждое приближение уменьшает ошибку в тысячи раз
+	target := getUnitVector(coords)
+	point := target
+	for i := 0; i < 3; i++ {
+		observed := getObservedDirection(point, epoch)
+		for axis := range point {
+			point[axis] += target[axis] - observed[axis]
+		}
+	}
+	return getVectorCoords(point, c.Radius)
+}
+
+// getObservedDirection возвращает направление на звезду point, видимое с движущейся Земли на эпоху epoch,
+// с учётом отклонения света Солнцем и аберрации.
+func getObservedDirection(point [3]float64, epoch Epoch) [3]float64 {
+	position, velocity := getEarthState(epoch)
+	distance := math.Sqrt(getDotProduct(position, position))
+	var sun [3]float64 // направление от Солнца на Землю
+	for axis := range sun {
+		sun[axis] = position[axis] / distance
+	}
+	point = getDeflected(point, sun, distance)
+	return getAberrated(point, velocity, distance)
+}
+
+// GetApparentCoords возвращает видимое место объекта на эпоху epoch с учётом его пространственного движения
+// (см. GetCoordsAt и SphericalCoords.GetApparent).
+func (ao *AstronomicalObject) GetApparentCoords(epoch Epoch) SphericalCoords {
+	return ao.GetCoordsAt(epoch).GetApparent(epoch)
+}
+
+// getDeflected возвращает направление на звезду point с учётом отклонения света Солнцем;
+// sun — направление от Солнца на наблюдателя, distance — расстояние до Солнца в а.е.
+func getDeflected(point, sun [3]float64, distance float64) [3]float64 {
+	// у самого Солнца поправка ограничивается, как в iauLdsun
+	limit := 1e-6 / math.Max(distance*distance, 1)
+	w := sunSchwarzschildRadius / distance / math.Max(1+getDotProduct(point, sun), limit)
+	deflection := getCrossProduct(point, getCrossProduct(sun, point))
+	for axis := range point {
+		point[axis] += w * deflection[axis]
+	}
+	return point
+}
+
+// getAberrated возвращает направление на звезду point с учётом аберрации (релятивистская формула, как в iauAb);
+// velocity — скорость наблюдателя в а.е. в сутки, distance — расстояние до Солнца в а.е.
+func getAberrated(point, velocity [3]float64, distance float64) [3]float64 {
+	var v [3]float64
+	for axis := range v {
+		v[axis] = velocity[axis] / SpeedOfLight
+	}
+	inverseLorentz := math.Sqrt(1 - getDotProduct(v, v))
+	pv := getDotProduct(point, v)
+	w1 := 1 + pv/(1+inverseLorentz)
+	w2 := sunSchwarzschildRadius / distance
+
+	var result [3]float64
+	for axis := range result {
+		result[axis] = point[axis]*inverseLorentz + w1*v[axis] + w2*(v[axis]-pv*point[axis])
+	}
+	length := math.Sqrt(getDotProduct(result, result))
+	for axis := range result {
+		result[axis] /= length
+	}
+	return result
+}
+
+// getEarthState возвращает гелиоцентрические положение (а.е.) и скорость (а.е. в сутки) Земли
+// в экваториальных координатах J2000 по упрощённой теории Солнца (Astronomical Almanac, точность около 0.01°).
+func getEarthState(epoch Epoch) (position, velocity [3]float64) {
+	days := float64(epoch - J2000)
+	meanLongitude := (280.460 + 0.9856474*days) * Degree
+	meanAnomaly := (357.528 + 0.9856003*days) * Degree
+	sinG, cosG := math.Sincos(meanAnomaly)
+	sin2G, cos2G := math.Sincos(2 * meanAnomaly)
+
+	// эклиптическая долгота Солнца отнесена к равноденствию даты; 1.397° в столетие — общая прецессия по долготе
+	longitude := meanLongitude + (1.915*sinG+0.020*sin2G)*Degree - 1.397*Degree*epoch.GetCenturies()
+	distance := 1.00014 - 0.01671*cosG - 0.00014*cos2G
+	longitudeRate := (0.9856474 + 0.9856003*(1.915*cosG+0.040*cos2G)*Degree) * Degree
+	distanceRate := 0.9856003 * Degree * (0.01671*sinG + 0.00028*sin2G)
+
+	sin, cos := math.Sincos(longitude)
+	sinObliquity, cosObliquity := math.Sincos(GetMeanObliquity(J2000))
+	// положение Земли противоположно положению Солнца
+	x, y := -distance*cos, -distance*sin
+	vx := -distanceRate*cos + distance*longitudeRate*sin
+	vy := -distanceRate*sin - distance*longitudeRate*cos
+	position = [3]float64{x, y * cosObliquity, y * sinObliquity}
+	velocity = [3]float64{vx, vy * cosObliquity, vy * sinObliquity}
+	return position, velocity
+}
@@ -0,0 +1,88 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+// Точность видимого места ограничена упрощённой теорией Солнца: около 0.1″.
+func TestGetApparent(t *testing.T) {
+	tests := []struct {
+		name   string
+		coords SphericalCoords // координаты J2000, перенесённые собственным движением на эпоху
+		epoch  Epoch
+		want   SphericalCoords
+	}{
+		{
+			// Meeus, пример 23.a: θ Persei
+			"Meeus 23.a", NewClockCoords(2, 44, 12.975, 49, 13, 39.90), Epoch(2462088.69),
+			NewClockCoords(2, 46, 14.390, 49, 21, 7.45),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apparent := test.coords.GetApparent(test.epoch)
+			if distance := apparent.GetDistance(test.want); distance > 0.1*ArcSecond {
+				t.Errorf("apparent %v, want %v (%.3f″)", apparent, test.want, distance/ArcSecond)
+			}
+		})
+	}
+}
+
+// Для звёзд с пространственным движением видимое место вычисляется из координат каталога J2000.
+func TestGetApparentCoords(t *testing.T) {
+	tests := []struct {
+		name   string
+		object AstronomicalObject
+		epoch  Epoch
+		want   SphericalCoords
+	}{
+		{
+			// Meeus, пример 23.a: θ Persei, собственное движение +0.03425 с и −0.0895″ в год
+			"Meeus 23.a",
+			AstronomicalObject{
+				Coords: NewClockCoords(2, 44, 11.986, 49, 13, 42.48),
+				Motion: SpaceMotion{
+					ProperMotionRA:  0.03425 * 15 * math.Cos(NewAngleFromDegrees(49, 13, 42.48).Radians()),
+					ProperMotionDec: -0.0895,
+				},
+			},
+			Epoch(2462088.69),
+			NewClockCoords(2, 46, 14.390, 49, 21, 7.45),
+		},
+		{
+			// тест iauAtci13 из t_sofa_c, собственное движение 2″ в год; прямое восхождение отсчитано от точки
+			// весеннего равноденствия (ri − eo). Параллакс 0.1″ смещает видимое место меньше допуска
+			"SOFA iauAtci13",
+			AstronomicalObject{
+				Coords: NewSphericalCoords(2.71, 0.174, 0),
+				Motion: SpaceMotion{
+					ProperMotionRA:  1e-5 * math.Cos(0.174) / ArcSecond,
+					ProperMotionDec: 5e-6 / ArcSecond,
+					Parallax:        0.1,
+					RadialVelocity:  55,
+				},
+			},
+			Epoch(2456165.5 + 0.401182685),
+			NewSphericalCoords(2.710121572968696744+0.002900618712657375647, 0.1729371367219539137, 0),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apparent := test.object.GetApparentCoords(test.epoch)
+			if distance := apparent.GetDistance(test.want); distance > 0.1*ArcSecond {
+				t.Errorf("apparent %v, want %v (%.3f″)", apparent, test.want, distance/ArcSecond)
+			}
+		})
+	}
+}
+
+func TestGetApparentRoundTrip(t *testing.T) {
+	c := NewClockCoords(2, 44, 12.975, 49, 13, 39.90)
+	epoch := Epoch(2462088.69)
+	back := getCatalogueFromApparent(c.GetApparent(epoch), epoch)
+	if math.Abs(back.Longitude.Radians()-c.Longitude.Radians()) > 1e-11 ||
+		math.Abs(back.Latitude.Radians()-c.Latitude.Radians()) > 1e-11 {
+		t.Errorf("round trip %v, want %v", back, c)
+	}
+}
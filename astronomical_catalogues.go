@@ -1,68 +1,361 @@
 package gorewind
 
+import "math"
+
 // gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
 // Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
 // Copyright © 2021 Dvoeglazyi
 // License: http://github.com/dvoeglazyi/gorewind/LICENSE
 
-// TODO объединение данных каталогов не сделано.
+// Источники данных при объединении каталогов.
+const (
+	SourceNames = "astrocat" // Astro Catalogue
+	SourceBSC   = "BSC"      // Yale Catalogue of Bright Stars
+	SourceNGC   = "NGC"      // New General Catalogue (включая IC)
+)
+
+// MergeOptions параметры объединения каталогов.
+type MergeOptions struct {
+	// KeepUnnamed сохраняет объекты BSC и NGC/IC, для которых не найдено ни одного названия.
+	KeepUnnamed bool
+	// CoordsPriority порядок источников при выборе координат. По умолчанию BSC, NGC, astrocat.
+	CoordsPriority []string
+	// MagnitudePriority порядок источников при выборе звёздной величины. По умолчанию BSC, NGC, astrocat.
+	MagnitudePriority []string
+	// MatchThreshold допустимое расстояние (в радианах) для сопоставления объектов по координатам,
+	// если сопоставление по номеру в каталоге и обозначению не удалось. Нулевое значение отключает сопоставление.
+	MatchThreshold float64
+	// CoordsTolerance расхождение координат (в радианах), начиная с которого фиксируется конфликт.
+	CoordsTolerance float64
+	// MagnitudeTolerance расхождение звёздных величин, начиная с которого фиксируется конфликт.
+	MagnitudeTolerance float64
+}
 
-func read(namesPath, bscPath, ngcPath, ngcNamesPath string) ([]*AstronomicalObject, error) {
-	namesRecords, err := ReadNamesCatalogue(namesPath)
-	if err != nil {
-		return nil, err
+// DefaultMergeOptions возвращает параметры объединения каталогов по умолчанию.
+func DefaultMergeOptions() MergeOptions {
+	return MergeOptions{
+		MatchThreshold:     Degree / 60,
+		CoordsTolerance:    Degree / 60,
+		MagnitudeTolerance: 0.1,
 	}
+}
+
+// MergeConflict расхождение данных разных источников для одного объекта.
+type MergeConflict struct {
+	Object     *AstronomicalObject
+	Field      string  // "coords" или "magnitude"
+	Source     string  // источник, значение которого выбрано
+	Rejected   string  // источник, значение которого отброшено
+	Difference float64 // расхождение: в радианах для координат, в звёздных величинах для блеска
+}
 
-	namesByIndex := make(map[uint]*AstronomicalObject)
-	namesByDesignation := make(map[Designation]*AstronomicalObject)
+// MergeReport отчёт об объединении каталогов.
+type MergeReport struct {
+	Conflicts []MergeConflict
+	// UnmatchedNames записи Astro Catalogue, не сопоставленные ни с BSC, ни с NGC/IC.
+	// Такие записи включаются в результат как есть.
+	UnmatchedNames []*AstronomicalObject
+	// DuplicateNames записи Astro Catalogue, указывающие на уже сопоставленный объект.
+	DuplicateNames []*AstronomicalObject
+	// Unnamed записи BSC и NGC/IC без названия.
+	// Включаются в результат только при MergeOptions.KeepUnnamed.
+	Unnamed []*AstronomicalObject
+	// PositionalMatches количество объектов, сопоставленных по координатам.
+	PositionalMatches int
+}
 
-	for _, name := range namesRecords {
-		if name.Catalogue == "HR" {
-			namesByIndex[name.Index] = name
+// ReadAstronomicalCatalogues читает и объединяет Astro Catalogue, BSC и NGC/IC.
+// Пустой путь означает, что соответствующий каталог не используется.
+func ReadAstronomicalCatalogues(namesPath, bscPath, ngcPath, ngcNamesPath string, options MergeOptions) ([]*AstronomicalObject, *MergeReport, error) {
+	var names, bsc, ngc []*AstronomicalObject
+	var err error
+	if namesPath != "" {
+		if names, err = ReadNamesCatalogue(namesPath); err != nil {
+			return nil, nil, err
+		}
+	}
+	if bscPath != "" {
+		if bsc, err = ReadBSCCatalogue(bscPath); err != nil {
+			return nil, nil, err
 		}
-		if name.Designation.BayerCode != rune(0) {
-			namesByDesignation[Designation{BayerCode: name.Designation.BayerCode, Constellation: name.Designation.Constellation}] = name
-		} else if name.Designation.FlamsteedCode != 0 {
-			namesByDesignation[Designation{FlamsteedCode: name.Designation.FlamsteedCode, Constellation: name.Designation.Constellation}] = name
+	}
+	if ngcPath != "" {
+		if ngc, err = ReadNGCCatalogue(ngcPath, ngcNamesPath); err != nil {
+			return nil, nil, err
 		}
 	}
+	result, report := MergeCatalogues(names, bsc, ngc, options)
+	return result, report, nil
+}
 
-	bscRecords, err := ReadBSCCatalogue(bscPath)
-	if err != nil {
-		return nil, err
+// MergeCatalogues объединяет записи Astro Catalogue, BSC и NGC/IC в единый список без повторов.
+// Записи сопоставляются по номеру в каталоге (HR, NGC, IC), затем по обозначению Байера или Флемстида,
+// затем, если задан MergeOptions.MatchThreshold, по координатам.
+// Исходные записи BSC и NGC/IC изменяются и входят в результат.
+func MergeCatalogues(names, bsc, ngc []*AstronomicalObject, options MergeOptions) ([]*AstronomicalObject, *MergeReport) {
+	m := merger{
+		options:       options,
+		report:        &MergeReport{},
+		byIndex:       make(map[catalogueIndex]*AstronomicalObject),
+		byDesignation: make(map[Designation]*AstronomicalObject),
+		used:          make(map[*AstronomicalObject]bool),
+	}
+	for _, name := range names {
+		m.addName(name)
 	}
 
+	type candidate struct {
+		record *AstronomicalObject
+		source string
+	}
+	var unmatched []candidate
+
 	var result []*AstronomicalObject
-	for _, record := range bscRecords {
-		nameRecord := namesByIndex[record.Index]
+	for _, record := range bsc {
+		nameRecord := m.findByIndex(record)
 		if nameRecord == nil {
-			if record.Designation.BayerCode != rune(0) {
-				nameRecord = namesByDesignation[Designation{BayerCode: record.Designation.BayerCode, Constellation: record.Designation.Constellation}]
-			} else if record.Designation.FlamsteedCode != 0 {
-				nameRecord = namesByDesignation[Designation{FlamsteedCode: record.Designation.FlamsteedCode, Constellation: record.Designation.Constellation}]
-			}
+			nameRecord = m.findByDesignation(record)
 		}
 		if nameRecord == nil {
+			unmatched = append(unmatched, candidate{record: record, source: SourceBSC})
+			continue
+		}
+		m.merge(record, SourceBSC, nameRecord)
+		result = append(result, record)
+	}
+	for _, record := range ngc {
+		nameRecord := m.findByIndex(record)
+		if nameRecord == nil {
+			unmatched = append(unmatched, candidate{record: record, source: SourceNGC})
+			continue
+		}
+		m.merge(record, SourceNGC, nameRecord)
+		result = append(result, record)
+	}
+
+	for _, c := range unmatched {
+		if nameRecord := m.findByCoords(c.record); nameRecord != nil {
+			m.report.PositionalMatches++
+			m.merge(c.record, c.source, nameRecord)
+			result = append(result, c.record)
+			continue
+		}
+		if c.record.Name != "" {
+			// named by NGC names file only
+			result = append(result, c.record)
 			continue
 		}
+		m.report.Unnamed = append(m.report.Unnamed, c.record)
+		if m.options.KeepUnnamed {
+			result = append(result, c.record)
+		}
+	}
+
+	for _, name := range m.names {
+		if !m.used[name] {
+			m.report.UnmatchedNames = append(m.report.UnmatchedNames, name)
+			result = append(result, name)
+		}
+	}
+	return result, m.report
+}
+
+type catalogueIndex struct {
+	catalogue string
+	index     uint
+}
+
+type merger struct {
+	options       MergeOptions
+	report        *MergeReport
+	names         []*AstronomicalObject // names without duplicates
+	byIndex       map[catalogueIndex]*AstronomicalObject
+	byDesignation map[Designation]*AstronomicalObject
+	used          map[*AstronomicalObject]bool
+	index         *SpatialIndex // записи Astro Catalogue с координатами, см. findByCoords
+}
+
+func (m *merger) addName(name *AstronomicalObject) {
+	hasKey, isNew := false, false
+	if name.Catalogue != "" && name.Index != 0 {
+		hasKey = true
+		key := catalogueIndex{catalogue: name.Catalogue, index: name.Index}
+		if m.byIndex[key] == nil {
+			m.byIndex[key] = name
+			isNew = true
+		}
+	}
+	if designation := getShortDesignation(name.Designation); designation != (Designation{}) {
+		hasKey = true
+		if m.byDesignation[designation] == nil {
+			m.byDesignation[designation] = name
+			isNew = true
+		}
+	}
+	if hasKey && !isNew {
+		m.report.DuplicateNames = append(m.report.DuplicateNames, name)
+		return
+	}
+	m.names = append(m.names, name)
+}
+
+func (m *merger) findByIndex(record *AstronomicalObject) *AstronomicalObject {
+	if name := m.byIndex[catalogueIndex{catalogue: record.Catalogue, index: record.Index}]; name != nil && !m.used[name] {
+		return name
+	}
+	return nil
+}
+
+func (m *merger) findByDesignation(record *AstronomicalObject) *AstronomicalObject {
+	designation := getShortDesignation(record.Designation)
+	if designation == (Designation{}) {
+		return nil
+	}
+	if name := m.byDesignation[designation]; name != nil && !m.used[name] {
+		return name
+	}
+	return nil
+}
+
+// findByCoords ищет ближайшую несопоставленную запись Astro Catalogue в пределах MatchThreshold.
+// Записи с координатами индексируются при первом вызове, поэтому поиск не перебирает все записи.
+func (m *merger) findByCoords(record *AstronomicalObject) *AstronomicalObject {
+	if m.options.MatchThreshold <= 0 || !hasCoords(record.Coords) {
+		return nil
+	}
+	if m.index == nil {
+		m.index = NewSpatialIndex()
+		for _, name := range m.names {
+			if hasCoords(name.Coords) {
+				m.index.Add(name)
+			}
+		}
+	}
+	for _, match := range m.index.Within(record.Coords, m.options.MatchThreshold) {
+		if name := match.Item.(*AstronomicalObject); !m.used[name] {
+			return name
+		}
+	}
+	return nil
+}
+
+// merge дополняет запись каталога данными из записи Astro Catalogue.
+func (m *merger) merge(record *AstronomicalObject, source string, nameRecord *AstronomicalObject) {
+	m.used[nameRecord] = true
+
+	if record.Name != "" && record.Name != nameRecord.Name {
+		record.AlternateNames = append([]string{record.Name}, record.AlternateNames...)
+	}
+	if nameRecord.Name != "" {
 		record.Name = nameRecord.Name
+	}
+	if nameRecord.LocalName != "" {
 		record.LocalName = nameRecord.LocalName
-		record.AlternateNames = nameRecord.AlternateNames
-		result = append(result, record)
 	}
+	record.AlternateNames = mergeNames(record.Name, nameRecord.AlternateNames, record.AlternateNames)
 
-	ngcRecords, err := ReadNGCCatalogue(ngcPath, ngcNamesPath)
-	if err != nil {
-		return nil, err
+	if record.Designation.BayerCode == rune(0) && record.Designation.FlamsteedCode == 0 && record.Designation.VariableStarCode == "" {
+		record.Designation.BayerCode = nameRecord.Designation.BayerCode
+		record.Designation.FlamsteedCode = nameRecord.Designation.FlamsteedCode
+		record.Designation.VariableStarCode = nameRecord.Designation.VariableStarCode
+	}
+	if record.Designation.InSystemIndex == 0 {
+		record.Designation.InSystemIndex = nameRecord.Designation.InSystemIndex
+	}
+	if record.Designation.Constellation == "" {
+		record.Designation.Constellation = nameRecord.Designation.Constellation
 	}
 
-	for _, record := range ngcRecords {
-		if record.Name != "" {
-			result = append(result, record)
+	if hasCoords(nameRecord.Coords) {
+		radius := record.Coords.Radius
+		if radius == 0 {
+			radius = nameRecord.Coords.Radius
+		}
+		if !hasCoords(record.Coords) {
+			record.Coords = nameRecord.Coords
+		} else {
+			difference := record.Coords.GetDistance(nameRecord.Coords)
+			chosen, rejected := m.choose(m.options.CoordsPriority, source)
+			if chosen == SourceNames {
+				record.Coords = nameRecord.Coords
+			}
+			if difference > m.options.CoordsTolerance {
+				m.addConflict(record, "coords", chosen, rejected, difference)
+			}
 		}
+		record.Coords.Radius = radius
 	}
 
-	// TODO
+	if nameRecord.Magnitude != 0 {
+		if record.Magnitude == 0 {
+			record.Magnitude = nameRecord.Magnitude
+		} else {
+			difference := math.Abs(record.Magnitude - nameRecord.Magnitude)
+			chosen, rejected := m.choose(m.options.MagnitudePriority, source)
+			if chosen == SourceNames {
+				record.Magnitude = nameRecord.Magnitude
+			}
+			if difference > m.options.MagnitudeTolerance {
+				m.addConflict(record, "magnitude", chosen, rejected, difference)
+			}
+		}
+	}
+}
+
+// choose выбирает между источником каталога и Astro Catalogue согласно приоритету.
+func (m *merger) choose(priority []string, source string) (chosen, rejected string) {
+	if getPriority(priority, source) <= getPriority(priority, SourceNames) {
+		return source, SourceNames
+	}
+	return SourceNames, source
+}
+
+func (m *merger) addConflict(record *AstronomicalObject, field, chosen, rejected string, difference float64) {
+	m.report.Conflicts = append(m.report.Conflicts, MergeConflict{
+		Object:     record,
+		Field:      field,
+		Source:     chosen,
+		Rejected:   rejected,
+		Difference: difference,
+	})
+}
+
+var defaultSourcePriority = []string{SourceBSC, SourceNGC, SourceNames}
 
-	return result, nil
+func getPriority(priority []string, source string) int {
+	if len(priority) == 0 {
+		priority = defaultSourcePriority
+	}
+	for i, s := range priority {
+		if s == source {
+			return i
+		}
+	}
+	return len(priority)
+}
+
+// getShortDesignation возвращает обозначение Байера или Флемстида с номером компонента без прочих полей,
+// пригодное как ключ: α¹ и α² одного созвездия — разные ключи, а α без номера совпадает только с α без номера.
+func getShortDesignation(d Designation) Designation {
+	if d.BayerCode != rune(0) {
+		return Designation{BayerCode: d.BayerCode, InSystemIndex: d.InSystemIndex, Constellation: d.Constellation}
+	} else if d.FlamsteedCode != 0 {
+		return Designation{FlamsteedCode: d.FlamsteedCode, InSystemIndex: d.InSystemIndex, Constellation: d.Constellation}
+	}
+	return Designation{}
+}
+
+// mergeNames объединяет списки названий без повторов и без основного названия.
+func mergeNames(name string, lists ...[]string) []string {
+	var result []string
+	seen := map[string]bool{name: true, "": true}
+	for _, list := range lists {
+		for _, s := range list {
+			if !seen[s] {
+				seen[s] = true
+				result = append(result, s)
+			}
+		}
+	}
+	return result
 }
@@ -0,0 +1,53 @@
+package gorewind
+
+import "testing"
+
+func TestMergeCataloguesComponents(t *testing.T) {
+	alpha1 := &AstronomicalObject{Name: "Alpha-1", Designation: Designation{BayerCode: 'α', InSystemIndex: 1, Constellation: "Cap"}}
+	alpha2 := &AstronomicalObject{Name: "Alpha-2", Designation: Designation{BayerCode: 'α', InSystemIndex: 2, Constellation: "Cap"}}
+	bsc1 := &AstronomicalObject{Catalogue: "HR", Index: 7747, Designation: alpha1.Designation}
+	bsc2 := &AstronomicalObject{Catalogue: "HR", Index: 7754, Designation: alpha2.Designation}
+
+	result, report := MergeCatalogues([]*AstronomicalObject{alpha1, alpha2}, []*AstronomicalObject{bsc2, bsc1}, nil, DefaultMergeOptions())
+	if len(report.DuplicateNames) != 0 {
+		t.Fatalf("duplicate names: %d, want 0", len(report.DuplicateNames))
+	}
+	if len(result) != 2 {
+		t.Fatalf("result: %d objects, want 2", len(result))
+	}
+	if bsc1.Name != "Alpha-1" || bsc2.Name != "Alpha-2" {
+		t.Errorf("names: %q, %q, want Alpha-1, Alpha-2", bsc1.Name, bsc2.Name)
+	}
+}
+
+func TestMergeCataloguesNoComponentFallback(t *testing.T) {
+	alpha1 := &AstronomicalObject{Name: "Alpha-1", Designation: Designation{BayerCode: 'α', InSystemIndex: 1, Constellation: "Cap"}}
+	bsc2 := &AstronomicalObject{Catalogue: "HR", Index: 7754, Designation: Designation{BayerCode: 'α', InSystemIndex: 2, Constellation: "Cap"}}
+
+	_, report := MergeCatalogues([]*AstronomicalObject{alpha1}, []*AstronomicalObject{bsc2}, nil, DefaultMergeOptions())
+	if bsc2.Name != "" {
+		t.Errorf("α² got name %q of α¹", bsc2.Name)
+	}
+	if len(report.UnmatchedNames) != 1 {
+		t.Errorf("unmatched names: %d, want 1", len(report.UnmatchedNames))
+	}
+}
+
+func TestMergeCataloguesByCoords(t *testing.T) {
+	near := &AstronomicalObject{Name: "Near", Coords: NewCoordsFromDegrees(10, 20)}
+	far := &AstronomicalObject{Name: "Far", Coords: NewCoordsFromDegrees(10.1, 20)}
+	missing := &AstronomicalObject{Name: "Missing"}
+	record := &AstronomicalObject{Catalogue: "NGC", Index: 1, Coords: NewCoordsFromDegrees(10.001, 20)}
+	unplaced := &AstronomicalObject{Catalogue: "NGC", Index: 2}
+
+	_, report := MergeCatalogues([]*AstronomicalObject{far, missing, near}, nil, []*AstronomicalObject{record, unplaced}, DefaultMergeOptions())
+	if record.Name != "Near" {
+		t.Errorf("matched %q, want Near", record.Name)
+	}
+	if unplaced.Name != "" {
+		t.Errorf("object without coords matched %q", unplaced.Name)
+	}
+	if report.PositionalMatches != 1 {
+		t.Errorf("positional matches: %d, want 1", report.PositionalMatches)
+	}
+}
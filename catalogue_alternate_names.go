@@ -0,0 +1,318 @@
+package gorewind
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// GeoNames alternate names.
+// Для использования с файлом alternateNamesV2.zip или с файлами отдельных стран из каталога alternatenames.
+// http://download.geonames.org/export/dump/
+
+// DefaultLanguage язык LocalName по умолчанию.
+const DefaultLanguage = "ru"
+
+// ReadAlternateNames читает альтернативные названия на языках languages (ISO 639, например, "ru").
+// Если языки не заданы, читаются все названия; полный файл alternateNamesV2.txt занимает несколько гигабайт.
+func ReadAlternateNames(path string, languages ...string) ([]*AlternateName, error) {
+	file, err := OpenCatalogue(path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := NewAlternateNamesReader(file)
+	reader.Languages = languages
+	records, err := reader.ReadAll()
+	return records, setErrorFile(err, path)
+}
+
+// ReadAlternateNamesFS читает альтернативные названия из файловой системы fsys, например, из embed.FS.
+func ReadAlternateNamesFS(fsys fs.FS, name string, languages ...string) ([]*AlternateName, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := NewAlternateNamesReader(file)
+	reader.Languages = languages
+	records, err := reader.ReadAll()
+	return records, setErrorFile(err, name)
+}
+
+// AlternateName альтернативное название объекта GeoNames.
+type AlternateName struct {
+	ID         uint   // идентификатор названия
+	GeoNameID  uint   // идентификатор объекта, Location.GeoNameID
+	Language   string // код языка ISO 639 или тип названия: post, iata, icao, link, wkdt и др.
+	Name       string
+	Preferred  bool   // официальное, предпочтительное название
+	Short      bool   // краткая форма, например, California для State of California
+	Colloquial bool   // разговорное название, например, Big Apple для New York
+	Historic   bool   // историческое название, например, Bombay для Mumbai
+	From, To   string // период использования названия, если указан
+}
+
+// alternateNamesFields названия полей записи alternateNamesV2.txt.
+var alternateNamesFields = []string{
+	"alternateNameId",
+	"geonameid",
+	"isolanguage",
+	"alternate name",
+	"isPreferredName",
+	"isShortName",
+	"isColloquial",
+	"isHistoric",
+	"from",
+	"to",
+}
+
+// AlternateNamesReader последовательно читает записи alternateNamesV2.txt.
+type AlternateNamesReader struct {
+	lineReader
+
+	// Languages если задано, читаются только названия на перечисленных языках.
+	// Остальные названия учитываются в статистике как пропущенные.
+	Languages []string
+}
+
+// NewAlternateNamesReader создаёт AlternateNamesReader, читающий файл из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewAlternateNamesReader(r io.Reader) *AlternateNamesReader {
+	return &AlternateNamesReader{lineReader: lineReader{source: r}}
+}
+
+// Read возвращает следующую запись или io.EOF, если записей больше нет.
+func (r *AlternateNamesReader) Read() (*AlternateName, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := getAlternateName(r.line, line)
+		if err != nil {
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !r.hasLanguage(name.Language) {
+			r.stats.Skipped++
+			continue
+		}
+		r.stats.Read++
+		return name, nil
+	}
+}
+
+func (r *AlternateNamesReader) hasLanguage(language string) bool {
+	if len(r.Languages) == 0 {
+		return true
+	}
+	for _, l := range r.Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadAll читает все оставшиеся записи.
+func (r *AlternateNamesReader) ReadAll() ([]*AlternateName, error) {
+	var result []*AlternateName
+	err := r.Visit(context.Background(), func(name *AlternateName) error {
+		result = append(result, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Visit вызывает fn для каждой оставшейся записи.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *AlternateNamesReader) Visit(ctx context.Context, fn func(*AlternateName) error) error {
+	return visit(ctx, func() error {
+		name, err := r.Read()
+		if err != nil {
+			return err
+		}
+		return fn(name)
+	})
+}
+
+func getAlternateName(lineNumber int, line string) (*AlternateName, error) {
+	record, err := newTabLine(CatalogueAlternateNames, lineNumber, line, alternateNamesFields)
+	if err != nil {
+		return nil, err
+	}
+	id, err := record.parseUint(0)
+	if err != nil {
+		return nil, err
+	}
+	geoNameID, err := record.parseUint(1)
+	if err != nil {
+		return nil, err
+	}
+	return &AlternateName{
+		ID:         uint(id),
+		GeoNameID:  uint(geoNameID),
+		Language:   record.fields[2],
+		Name:       record.fields[3],
+		Preferred:  record.fields[4] == "1",
+		Short:      record.fields[5] == "1",
+		Colloquial: record.fields[6] == "1",
+		Historic:   record.fields[7] == "1",
+		From:       record.fields[8],
+		To:         record.fields[9],
+	}, nil
+}
+
+// getRank возвращает приоритет названия при выборе LocalName: чем меньше, тем лучше.
+func (n *AlternateName) getRank() int {
+	rank := 0
+	if n.Historic {
+		rank += 8
+	}
+	if n.Colloquial {
+		rank += 4
+	}
+	if !n.Preferred {
+		rank += 2
+	}
+	if !n.Short {
+		rank++
+	}
+	return rank
+}
+
+// NameResolver выбирает название объекта GeoNames на заданном языке по alternateNamesV2.txt.
+type NameResolver struct {
+	names map[uint][]*AlternateName
+}
+
+// NewNameResolver создаёт NameResolver по списку альтернативных названий.
+func NewNameResolver(names []*AlternateName) *NameResolver {
+	resolver := NameResolver{names: make(map[uint][]*AlternateName)}
+	for _, name := range names {
+		resolver.Add(name)
+	}
+	return &resolver
+}
+
+// Add добавляет альтернативное название, например, при чтении файла через AlternateNamesReader.Visit.
+func (r *NameResolver) Add(name *AlternateName) {
+	r.names[name.GeoNameID] = append(r.names[name.GeoNameID], name)
+}
+
+// GetName возвращает название объекта geoNameID на языке language.
+// Предпочтительные и краткие названия выбираются раньше остальных, разговорные и исторические — в последнюю очередь.
+func (r *NameResolver) GetName(geoNameID uint, language string) (string, bool) {
+	var best *AlternateName
+	for _, name := range r.names[geoNameID] {
+		if name.Language != language {
+			continue
+		}
+		if best == nil || name.getRank() < best.getRank() {
+			best = name
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.Name, true
+}
+
+// GetLocalName возвращает название location на языке language.
+// Если в alternateNamesV2.txt названия нет, оно выбирается из Location.AlternateNames по письменности языка.
+func (r *NameResolver) GetLocalName(location *Location, language string) string {
+	if r != nil {
+		if name, ok := r.GetName(location.GeoNameID, language); ok {
+			return name
+		}
+	}
+	return GetLocalName(location.AlternateNames, language)
+}
+
+// languageScripts письменности языков, отличные от латиницы.
+var languageScripts = map[string][]*unicode.RangeTable{
+	"ru": {unicode.Cyrillic},
+	"uk": {unicode.Cyrillic},
+	"be": {unicode.Cyrillic},
+	"bg": {unicode.Cyrillic},
+	"sr": {unicode.Cyrillic},
+	"mk": {unicode.Cyrillic},
+	"kk": {unicode.Cyrillic},
+	"ky": {unicode.Cyrillic},
+	"tg": {unicode.Cyrillic},
+	"mn": {unicode.Cyrillic},
+	"tt": {unicode.Cyrillic},
+	"ba": {unicode.Cyrillic},
+	"el": {unicode.Greek},
+	"hy": {unicode.Armenian},
+	"ka": {unicode.Georgian},
+	"he": {unicode.Hebrew},
+	"yi": {unicode.Hebrew},
+	"ar": {unicode.Arabic},
+	"fa": {unicode.Arabic},
+	"ur": {unicode.Arabic},
+	"hi": {unicode.Devanagari},
+	"mr": {unicode.Devanagari},
+	"ne": {unicode.Devanagari},
+	"bn": {unicode.Bengali},
+	"ta": {unicode.Tamil},
+	"th": {unicode.Thai},
+	"lo": {unicode.Lao},
+	"km": {unicode.Khmer},
+	"my": {unicode.Myanmar},
+	"am": {unicode.Ethiopic},
+	"zh": {unicode.Han},
+	"ja": {unicode.Han, unicode.Hiragana, unicode.Katakana},
+	"ko": {unicode.Hangul, unicode.Han},
+}
+
+// GetLocalName возвращает первое из названий, записанное письменностью языка language.
+// Для языков без собственной письменности в languageScripts используется латиница.
+// Определить по письменности можно только язык, но не диалект: для "uk" подойдёт и русское название.
+func GetLocalName(names []string, language string) string {
+	scripts, ok := languageScripts[language]
+	if !ok {
+		scripts = []*unicode.RangeTable{unicode.Latin}
+	}
+	for _, name := range names {
+		if isScriptText(name, scripts) {
+			return name
+		}
+	}
+	return ""
+}
+
+// isScriptText проверяет, что все буквы text относятся к письменностям scripts.
+// Пробелы, дефисы, цифры и знаки препинания допускаются: «Нижний Новгород», «Ростов-на-Дону».
+func isScriptText(text string, scripts []*unicode.RangeTable) bool {
+	hasLetters := false
+	for _, r := range strings.TrimSpace(text) {
+		if !unicode.IsLetter(r) {
+			if unicode.IsSpace(r) || unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.Is(unicode.Mn, r) {
+				continue
+			}
+			return false
+		}
+		if !unicode.In(r, scripts...) {
+			return false
+		}
+		hasLetters = true
+	}
+	return hasLetters
+}
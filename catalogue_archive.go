@@ -0,0 +1,177 @@
+package gorewind
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// Каталоги распространяются в сжатом виде: CDS публикует catalog.gz, GeoNames — cities15000.zip.
+// Формат определяется по сигнатуре в начале файла, а не по расширению.
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zipMagic   = []byte("PK\x03\x04")
+)
+
+// OpenCatalogue открывает файл каталога, прозрачно распаковывая gzip, bzip2 и zip.
+// Для zip-архива member задаёт имя файла внутри архива. Если member пуст, выбирается единственный файл архива
+// или файл с тем же именем, что и архив (cities15000.zip → cities15000.txt).
+func OpenCatalogue(path, member string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := decompress(file, member, filepath.Base(path))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &catalogueFile{Reader: reader, file: file}, nil
+}
+
+// OpenCatalogueFS открывает файл каталога из файловой системы fsys аналогично OpenCatalogue.
+func OpenCatalogueFS(fsys fs.FS, name, member string) (io.ReadCloser, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := decompress(file, member, path.Base(name))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &catalogueFile{Reader: reader, file: file}, nil
+}
+
+// Decompress возвращает распакованное содержимое r, если это gzip, bzip2 или zip, и r без изменений в остальных случаях.
+// Для zip-архива member задаёт имя файла внутри архива; если member пуст, архив должен содержать единственный файл.
+// Если r не реализует io.ReaderAt, zip-архив целиком читается в память.
+func Decompress(r io.Reader, member string) (io.Reader, error) {
+	return decompress(r, member, "")
+}
+
+type catalogueFile struct {
+	io.Reader
+	file io.Closer
+}
+
+func (f *catalogueFile) Close() error {
+	if closer, ok := f.Reader.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			f.file.Close()
+			return err
+		}
+	}
+	return f.file.Close()
+}
+
+// decompress распаковывает r; name — имя архива, по которому выбирается файл внутри zip.
+func decompress(r io.Reader, member, name string) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(zipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(buffered)
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return bzip2.NewReader(buffered), nil
+	case bytes.HasPrefix(magic, zipMagic):
+		return openZipMember(r, buffered, member, name)
+	}
+	return buffered, nil
+}
+
+func openZipMember(r io.Reader, buffered io.Reader, member, name string) (io.Reader, error) {
+	readerAt, size, err := getReaderAt(r)
+	if err != nil {
+		return nil, err
+	}
+	if readerAt == nil {
+		data, err := io.ReadAll(buffered)
+		if err != nil {
+			return nil, err
+		}
+		readerAt, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	archive, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return nil, err
+	}
+	file, err := selectZipMember(archive.File, member, name)
+	if err != nil {
+		return nil, err
+	}
+	return file.Open()
+}
+
+// getReaderAt возвращает r как io.ReaderAt, если его размер известен, или nil.
+func getReaderAt(r io.Reader) (io.ReaderAt, int64, error) {
+	switch file := r.(type) {
+	case interface {
+		io.ReaderAt
+		Size() int64
+	}:
+		return file, file.Size(), nil
+	case interface {
+		io.ReaderAt
+		Stat() (fs.FileInfo, error)
+	}:
+		info, err := file.Stat()
+		if err != nil {
+			return nil, 0, err
+		}
+		return file, info.Size(), nil
+	}
+	return nil, 0, nil
+}
+
+func selectZipMember(files []*zip.File, member, name string) (*zip.File, error) {
+	var candidates []*zip.File
+	for _, file := range files {
+		if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+		if member != "" && (file.Name == member || path.Base(file.Name) == member) {
+			return file, nil
+		}
+		candidates = append(candidates, file)
+	}
+	if member != "" {
+		return nil, fmt.Errorf("file %q not found in zip archive", member)
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	if base := trimExtension(name); base != "" {
+		for _, file := range candidates {
+			if trimExtension(path.Base(file.Name)) == base {
+				return file, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("zip archive contains %d files, member must be specified", len(candidates))
+}
+
+func trimExtension(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name))
+}
@@ -0,0 +1,68 @@
+package gorewind
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCityLine = "524901\tMoscow\tMoscow\tMoskva\t55.75222\t37.61556\tP\tPPLC\tRU\t\t48\t\t\t\t10381222\t\t144\tEurope/Moscow\t2022-12-10\n"
+
+func TestReadCompressedCatalogue(t *testing.T) {
+	dir := t.TempDir()
+
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	writer.Write([]byte(testCityLine))
+	writer.Close()
+	gzipPath := filepath.Join(dir, "cities.txt.gz")
+	if err := os.WriteFile(gzipPath, gzipped.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var zipped bytes.Buffer
+	archive := zip.NewWriter(&zipped)
+	for name, data := range map[string]string{"readme.txt": "readme", "cities15000.txt": testCityLine} {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Write([]byte(data))
+	}
+	archive.Close()
+	zipPath := filepath.Join(dir, "cities15000.zip")
+	if err := os.WriteFile(zipPath, zipped.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		path   string
+		member string
+	}{
+		{path: gzipPath},
+		{path: zipPath},
+		{path: zipPath, member: "cities15000.txt"},
+	} {
+		locations, _, err := ReadCitiesCatalogueWithOptions(test.path, ReadOptions{Member: test.member})
+		if err != nil {
+			t.Errorf("%s %q: %v", test.path, test.member, err)
+			continue
+		}
+		if len(locations) != 1 || locations[0].Name != "Moscow" {
+			t.Errorf("%s %q: %d locations, want Moscow", test.path, test.member, len(locations))
+		}
+	}
+}
+
+// Распакованные данные не распаковываются повторно, даже если начинаются с сигнатуры архива.
+func TestReaderDoesNotDecompress(t *testing.T) {
+	_, err := NewCitiesReader(strings.NewReader("PK\x03\x04\tMoscow\n")).Read()
+	if !errors.Is(err, ErrFieldCount) {
+		t.Errorf("error %v, want ErrFieldCount", err)
+	}
+}
@@ -1,10 +1,10 @@
 package gorewind
 
 import (
-	"bufio"
+	"context"
 	"io"
-	"os"
-	"strconv"
+	"io/fs"
+	"math"
 	"strings"
 )
 
@@ -17,48 +17,146 @@ import (
 // http://cdsarc.u-strasbg.fr/viz-bin/Cat?V/50
 
 func ReadBSCCatalogue(path string) ([]*AstronomicalObject, error) {
-	file, err := os.Open(path)
+	records, _, err := ReadBSCCatalogueWithOptions(path, ReadOptions{})
+	return records, err
+}
+
+// ReadBSCCatalogueWithOptions читает каталог с параметрами options, например, в нестрогом режиме,
+// и возвращает статистику чтения.
+func ReadBSCCatalogueWithOptions(path string, options ReadOptions) ([]*AstronomicalObject, ReadStats, error) {
+	file, err := OpenCatalogue(path, options.Member)
+	if err != nil {
+		return nil, ReadStats{}, err
+	}
+	defer file.Close()
+
+	reader := NewBSCReader(file)
+	reader.ReadOptions = options
+	records, err := reader.ReadAll()
+	return records, setStatsErrorFile(reader.Stats(), path), setErrorFile(err, path)
+}
+
+// ReadBSCCatalogueFS читает каталог из файловой системы fsys, например, из embed.FS.
+func ReadBSCCatalogueFS(fsys fs.FS, name string) ([]*AstronomicalObject, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var records []*AstronomicalObject
-	reader := bufio.NewReader(file)
+	records, err := NewBSCReader(file).ReadAll()
+	return records, setErrorFile(err, name)
+}
+
+// BSCReader последовательно читает записи каталога BSC.
+type BSCReader struct {
+	lineReader
+}
+
+// NewBSCReader создаёт BSCReader, читающий каталог из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewBSCReader(r io.Reader) *BSCReader {
+	return &BSCReader{lineReader: lineReader{source: r}}
+}
+
+// Read возвращает следующую запись каталога или io.EOF, если записей больше нет.
+// Записи без координат пропускаются.
+func (r *BSCReader) Read() (*AstronomicalObject, error) {
 	for {
-		fields, err := reader.ReadString('\n')
+		record, err := r.readRecord()
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
 			return nil, err
 		}
+		if !record.HasCoords {
+			// catalogue has some records without coordinates
+			// that records will be skipped
+			r.stats.Skipped++
+			continue
+		}
+		r.stats.Read++
+		return record.GetAstronomicalObject(), nil
+	}
+}
 
-		record, err := getRecord(fields)
+// ReadRecord возвращает следующую полную запись каталога, включая записи без координат, или io.EOF.
+func (r *BSCReader) ReadRecord() (*BSCRecord, error) {
+	record, err := r.readRecord()
+	if err != nil {
+		return nil, err
+	}
+	r.stats.Read++
+	return record, nil
+}
+
+func (r *BSCReader) readRecord() (*BSCRecord, error) {
+	for {
+		line, err := r.readLine()
 		if err != nil {
 			return nil, err
-		} else if record == nil {
+		}
+
+		record, err := getBSCRecord(&fixedLine{catalogue: CatalogueBSC, line: r.line, s: line})
+		if err != nil {
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
 			continue
 		}
+		return record, nil
+	}
+}
+
+// ReadAll читает все оставшиеся записи каталога.
+func (r *BSCReader) ReadAll() ([]*AstronomicalObject, error) {
+	var records []*AstronomicalObject
+	err := r.Visit(context.Background(), func(record *AstronomicalObject) error {
 		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return records, nil
 }
 
-func getDesignation(s string) (Designation, error) {
+// Visit вызывает fn для каждой оставшейся записи каталога.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *BSCReader) Visit(ctx context.Context, fn func(*AstronomicalObject) error) error {
+	return visit(ctx, func() error {
+		record, err := r.Read()
+		if err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
+
+// VisitRecords вызывает fn для каждой оставшейся полной записи каталога.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *BSCReader) VisitRecords(ctx context.Context, fn func(*BSCRecord) error) error {
+	return visit(ctx, func() error {
+		record, err := r.ReadRecord()
+		if err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
+
+func getDesignation(l *fixedLine) (Designation, error) {
 	result := Designation{
-		Constellation: strings.TrimSpace(s[7:]),
-		BayerCode:     GetBayerRune(strings.TrimSpace(s[3:6])),
+		Constellation: l.text(12, 14),
+		BayerCode:     GetBayerRune(l.text(8, 10)),
 	}
-	if s[6] != ' ' {
-		index, err := strconv.ParseUint(string(s[6]), 10, 64)
+	if l.text(11, 11) != "" {
+		index, err := l.parseUint("Name", 11, 11)
 		if err != nil {
 			return result, err
 		}
 		result.InSystemIndex = uint(index)
 	}
-	if code := strings.TrimSpace(s[:3]); code != "" {
-		index, err := strconv.ParseUint(code, 10, 64)
+	if l.text(5, 7) != "" {
+		index, err := l.parseUint("Name", 5, 7)
 		if err != nil {
 			return result, err
 		}
@@ -67,102 +165,354 @@ func getDesignation(s string) (Designation, error) {
 	return result, nil
 }
 
-func getRecord(s string) (*AstronomicalObject, error) {
-	index, err := strconv.ParseUint(strings.TrimSpace(s[:4]), 10, 64)
+// BSCRecord запись Yale Catalogue of Bright Stars, 5th Revised Ed. (V/50).
+// Отсутствующие в каталоге числовые значения с плавающей точкой равны NaN, номера в каталогах — 0.
+type BSCRecord struct {
+	HR          uint        // номер в Harvard Revised
+	Name        string      // обозначение Байера и/или Флемстида в виде каталога, например, "21Alp And"
+	Designation Designation // разобранное обозначение Байера и/или Флемстида
+	DM          string      // обозначение в Durchmusterung
+	HD          uint        // номер в Henry Draper Catalogue
+	SAO         uint        // номер в SAO Catalogue
+	FK5         uint        // номер в FK5
+
+	IRSource      bool   // инфракрасный источник
+	IRReference   string // код ссылки на инфракрасный источник
+	MultipleCode  string // код двойной или кратной звезды: A, W, D, I, R, S
+	ADS           string // обозначение в Aitken's Double Star Catalogue
+	ADSComponents string // компоненты в ADS
+	VariableID    string // обозначение переменной звезды
+
+	HasCoords   bool            // в каталоге есть координаты объекта
+	CoordsB1900 SphericalCoords // равноденствие B1900, эпоха 1900.0
+	Coords      SphericalCoords // равноденствие J2000, эпоха 2000.0
+	Galactic    SphericalCoords // галактические координаты
+
+	VMagnitude            float64 // визуальная звёздная величина
+	VMagnitudeCode        string  // код визуальной звёздной величины: H, R
+	VMagnitudeUncertainty string  // флаг неточности визуальной звёздной величины: ":", "?"
+	BV                    float64 // показатель цвета B-V в системе UBV
+	BVUncertainty         string  // флаг неточности B-V
+	UB                    float64 // показатель цвета U-B в системе UBV
+	UBUncertainty         string  // флаг неточности U-B
+	RI                    float64 // показатель цвета R-I
+	RISystem              string  // система R-I: C (Cousin), E (Eggen), ":", "?", D
+	SpectralType          string  // спектральный класс
+	SpectralTypeCode      string  // код спектрального класса: e, v, t
+
+	ProperMotionRA     float64 // годичное собственное движение по прямому восхождению μα·cos δ J2000 (FK5), угловые секунды в год
+	ProperMotionDec    float64 // годичное собственное движение по склонению J2000 (FK5), угловые секунды в год
+	DynamicalParallax  bool    // параллакс динамический, а не тригонометрический
+	Parallax           float64 // параллакс в угловых секундах
+	RadialVelocity     float64 // гелиоцентрическая лучевая скорость, км/с
+	RadialVelocityNote string  // комментарий к лучевой скорости: V, ?, SB, SB1, SB2, SBO
+
+	RotationalVelocityLimit       string  // ограничение скорости вращения: "<", "=", ">"
+	RotationalVelocity            float64 // скорость вращения v sin i, км/с
+	RotationalVelocityUncertainty string  // флаг неточности и переменности скорости вращения: ":", "v"
+
+	MagnitudeDifference float64 // разность звёздных величин компонентов двойной или самых ярких компонентов кратной звезды
+	Separation          float64 // расстояние между компонентами в угловых секундах
+	ComponentsID        string  // обозначения компонентов, к которым относятся разность величин и расстояние
+	ComponentsCount     uint    // количество компонентов кратной звезды
+	HasNote             bool    // у звезды есть примечание в файле notes
+	Notes               BSCNote // примечания, см. ReadBSCNotes и AttachBSCNotes
+}
+
+func (r *BSCRecord) GetCoords() SphericalCoords {
+	return r.Coords
+}
+
+// GetConstellation возвращает сокращённое название созвездия, например, And.
+func (r *BSCRecord) GetConstellation() string {
+	return r.Designation.Constellation
+}
+
+// GetAstronomicalObject возвращает небесный объект, соответствующий записи каталога.
+func (r *BSCRecord) GetAstronomicalObject() *AstronomicalObject {
+	result := AstronomicalObject{
+		Catalogue:   "HR",
+		Index:       r.HR,
+		Designation: r.Designation,
+		Coords:      r.Coords,
+	}
+	if !math.IsNaN(r.VMagnitude) {
+		result.Magnitude = r.VMagnitude
+	}
+	for _, field := range []struct {
+		value  float64
+		target *float64
+	}{
+		{r.ProperMotionRA, &result.Motion.ProperMotionRA},
+		{r.ProperMotionDec, &result.Motion.ProperMotionDec},
+		{r.Parallax, &result.Motion.Parallax},
+		{r.RadialVelocity, &result.Motion.RadialVelocity},
+	} {
+		if !math.IsNaN(field.value) {
+			*field.target = field.value
+		}
+	}
+	return &result
+}
+
+func getBSCRecord(l *fixedLine) (*BSCRecord, error) {
+	index, err := l.parseUint("HR", 1, 4)
 	if err != nil {
 		return nil, err
 	}
-	longitudeHoursString := strings.TrimSpace(s[75:77])
-	if longitudeHoursString == "" {
-		// catalogue has some records without coordinates
-		// that records will be skipped
-		return nil, nil
+	result := BSCRecord{
+		HR:   uint(index),
+		Name: l.text(5, 14),
+		DM:   l.text(15, 25),
+
+		IRSource:      l.text(42, 42) == "I",
+		IRReference:   l.text(43, 43),
+		MultipleCode:  l.text(44, 44),
+		ADS:           l.text(45, 49),
+		ADSComponents: l.text(50, 51),
+		VariableID:    l.text(52, 60),
+
+		VMagnitudeCode:        l.text(108, 108),
+		VMagnitudeUncertainty: l.text(109, 109),
+		BVUncertainty:         l.text(115, 115),
+		UBUncertainty:         l.text(121, 121),
+		RISystem:              l.text(127, 127),
+		SpectralType:          l.text(128, 147),
+		SpectralTypeCode:      l.text(148, 148),
+
+		DynamicalParallax:  l.text(161, 161) == "D",
+		RadialVelocityNote: l.text(171, 174),
+
+		RotationalVelocityLimit:       l.text(175, 176),
+		RotationalVelocityUncertainty: l.text(180, 180),
+
+		ComponentsID: l.text(191, 194),
+		HasNote:      l.text(197, 197) == "*",
+	}
+	if result.Designation, err = getDesignation(l); err != nil {
+		return nil, err
 	}
 
-	longitudeHours, err := strconv.ParseUint(longitudeHoursString, 10, 64)
+	for _, field := range []struct {
+		name       string
+		start, end int
+		value      *uint
+	}{
+		{name: "HD", start: 26, end: 31, value: &result.HD},
+		{name: "SAO", start: 32, end: 37, value: &result.SAO},
+		{name: "FK5", start: 38, end: 41, value: &result.FK5},
+		{name: "MultCnt", start: 195, end: 196, value: &result.ComponentsCount},
+	} {
+		value, err := l.optionalUint(field.name, field.start, field.end)
+		if err != nil {
+			return nil, err
+		}
+		*field.value = uint(value)
+	}
+
+	for _, field := range []struct {
+		name       string
+		start, end int
+		value      *float64
+	}{
+		{name: "Vmag", start: 103, end: 107, value: &result.VMagnitude},
+		{name: "B-V", start: 110, end: 114, value: &result.BV},
+		{name: "U-B", start: 116, end: 120, value: &result.UB},
+		{name: "R-I", start: 122, end: 126, value: &result.RI},
+		{name: "pmRA", start: 149, end: 154, value: &result.ProperMotionRA},
+		{name: "pmDE", start: 155, end: 160, value: &result.ProperMotionDec},
+		{name: "Parallax", start: 162, end: 166, value: &result.Parallax},
+		{name: "RadVel", start: 167, end: 170, value: &result.RadialVelocity},
+		{name: "RotVel", start: 177, end: 179, value: &result.RotationalVelocity},
+		{name: "Dmag", start: 181, end: 184, value: &result.MagnitudeDifference},
+		{name: "Sep", start: 185, end: 190, value: &result.Separation},
+	} {
+		if *field.value, err = l.optionalFloat(field.name, field.start, field.end); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.text(76, 77) != "" {
+		result.HasCoords = true
+		if l.text(61, 62) != "" {
+			if result.CoordsB1900, err = getBSCCoords(l, 61, "1900"); err != nil {
+				return nil, err
+			}
+		}
+		if result.Coords, err = getBSCCoords(l, 76, ""); err != nil {
+			return nil, err
+		}
+		galacticLongitude, err := l.parseFloat("GLON", 91, 96)
+		if err != nil {
+			return nil, err
+		}
+		galacticLatitude, err := l.parseFloat("GLAT", 97, 102)
+		if err != nil {
+			return nil, err
+		}
+		result.Galactic = NewCoordsFromDegrees(galacticLongitude, galacticLatitude)
+	}
+	return &result, nil
+}
+
+// getBSCCoords читает прямое восхождение и склонение, занимающие 15 байтов начиная со start.
+// Знак склонения хранится отдельно от градусов, поэтому склонение от -1° до 0° не теряет знак.
+func getBSCCoords(l *fixedLine, start int, suffix string) (SphericalCoords, error) {
+	hours, err := l.parseUint("RAh"+suffix, start, start+1)
 	if err != nil {
-		return nil, err
+		return SphericalCoords{}, err
 	}
-	longitudeMinutes, err := strconv.ParseUint(strings.TrimSpace(s[77:79]), 10, 64)
+	minutes, err := l.parseUint("RAm"+suffix, start+2, start+3)
 	if err != nil {
-		return nil, err
+		return SphericalCoords{}, err
 	}
-	longitudeSeconds, err := strconv.ParseFloat(strings.TrimSpace(s[79:83]), 64)
+	seconds, err := l.parseFloat("RAs"+suffix, start+4, start+7)
 	if err != nil {
-		return nil, err
+		return SphericalCoords{}, err
 	}
 
-	latitudeDegrees, err := strconv.ParseInt(strings.TrimSpace(s[83:86]), 10, 64)
+	latitudeDegrees, err := l.parseUint("DEd"+suffix, start+9, start+10)
 	if err != nil {
-		return nil, err
+		return SphericalCoords{}, err
 	}
-	latitudeMinutes, err := strconv.ParseUint(strings.TrimSpace(s[86:88]), 10, 64)
+	latitudeMinutes, err := l.parseUint("DEm"+suffix, start+11, start+12)
 	if err != nil {
-		return nil, err
+		return SphericalCoords{}, err
 	}
-	latitudeSeconds, err := strconv.ParseUint(strings.TrimSpace(s[88:90]), 10, 64)
+	latitudeSeconds, err := l.parseUint("DEs"+suffix, start+13, start+14)
 	if err != nil {
-		return nil, err
+		return SphericalCoords{}, err
 	}
 
-	var magnitude float64
-	if magnitudeString := strings.TrimSpace(s[102:107]); magnitudeString != "" {
-		if magnitude, err = strconv.ParseFloat(magnitudeString, 64); err != nil {
-			return nil, err
-		}
+	longitude := 15 * (float64(hours) + float64(minutes)/60 + seconds/3600)
+	latitude := float64(latitudeDegrees) + float64(latitudeMinutes)/60 + float64(latitudeSeconds)/3600
+	if l.raw(start+8, start+8) == "-" {
+		latitude = -latitude
 	}
+	return NewCoordsFromDegrees(longitude, latitude), nil
+}
 
-	result := AstronomicalObject{
-		Catalogue: "HR",
-		Index:     uint(index),
-		Coords:    NewClockCoords(uint(longitudeHours), float64(longitudeMinutes), longitudeSeconds, int(latitudeDegrees), float64(latitudeMinutes), float64(latitudeSeconds)),
-		Magnitude: magnitude,
-	}
-	if result.Designation, err = getDesignation(s[4:14]); err != nil {
+// ReadBSCNotes читает файл примечаний каталога (notes), в том числе сжатый, и возвращает примечания по номеру HR.
+func ReadBSCNotes(path string) (map[uint]BSCNote, error) {
+	file, err := OpenCatalogue(path, "")
+	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+	defer file.Close()
+
+	notes, err := NewBSCNotesReader(file).ReadAll()
+	return notes, setErrorFile(err, path)
 }
 
-func readBSCNotes(path string) (map[uint]BSCNote, error) {
-	namesFile, err := os.Open(path)
+// ReadBSCNotesFS читает примечания из файловой системы fsys, например, из embed.FS.
+func ReadBSCNotesFS(fsys fs.FS, name string) (map[uint]BSCNote, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
 	if err != nil {
 		return nil, err
 	}
-	defer namesFile.Close()
+	defer file.Close()
 
+	notes, err := NewBSCNotesReader(file).ReadAll()
+	return notes, setErrorFile(err, name)
+}
+
+// BSCNotesReader читает файл примечаний BSC (notes).
+type BSCNotesReader struct {
+	lineReader
+}
+
+// NewBSCNotesReader создаёт BSCNotesReader, читающий примечания из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewBSCNotesReader(r io.Reader) *BSCNotesReader {
+	return &BSCNotesReader{lineReader: lineReader{source: r}}
+}
+
+// ReadAll читает все примечания и возвращает их по номерам HR.
+func (r *BSCNotesReader) ReadAll() (map[uint]BSCNote, error) {
 	notesMap := make(map[uint]BSCNote)
-	reader := bufio.NewReader(namesFile)
+	var lastIndex uint
+	var lastCategory BSCNoteCategory
 	for {
-		s, err := reader.ReadString('\n')
+		s, err := r.readLine()
 		if err == io.EOF {
 			break
 		} else if err != nil {
 			return nil, err
-		} else if len(s) < 41 {
+		}
+		l := fixedLine{catalogue: CatalogueBSCNotes, line: r.line, s: s}
+		if l.text(1, 11) == "" {
+			r.stats.Skipped++
 			continue
 		}
-		index, err := strconv.ParseUint(strings.TrimSpace(s[1:5]), 10, 64)
+
+		index, err := l.parseUint("HR", 2, 5)
 		if err != nil {
-			return nil, err
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
 		}
-		noteIndex, err := strconv.ParseUint(strings.TrimSpace(s[5:7]), 10, 64)
+		noteIndex, err := l.parseUint("count", 6, 7)
 		if err != nil {
-			return nil, err
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		category := BSCNoteCategory(strings.TrimSpace(strings.TrimSuffix(l.text(8, 11), ":")))
+		if category == "" && uint(index) == lastIndex {
+			// continuation of the previous remark
+			category = lastCategory
+		}
+		lastIndex, lastCategory = uint(index), category
+
+		note := notesMap[uint(index)]
+		if note == nil {
+			note = make(BSCNote)
+			notesMap[uint(index)] = note
 		}
-		key := strings.TrimSpace(s[7:11])
-		value := s[12:]
-		notesMap[uint(index)].add(key, uint(noteIndex), value)
+		note.add(category, uint(noteIndex), strings.TrimSpace(l.raw(13, len(s))))
+		r.stats.Read++
 	}
 	return notesMap, nil
 }
 
-type BSCNote map[string]string
+// BSCNoteCategory категория примечания BSC.
+type BSCNoteCategory string
+
+// Категории примечаний BSC.
+const (
+	BSCNoteColors              BSCNoteCategory = "C"   // показатели цвета
+	BSCNoteMultiplicity        BSCNoteCategory = "D"   // двойные и кратные звёзды
+	BSCNoteDynamicalParallax   BSCNoteCategory = "DYN" // динамические параллаксы
+	BSCNoteGroup               BSCNoteCategory = "G"   // принадлежность к группе
+	BSCNoteMiscellaneous       BSCNoteCategory = "M"   // прочее
+	BSCNoteNames               BSCNoteCategory = "N"   // названия звезды
+	BSCNotePolarization        BSCNoteCategory = "P"   // поляризация
+	BSCNoteRadius              BSCNoteCategory = "R"   // радиус или диаметр звезды
+	BSCNoteVelocity            BSCNoteCategory = "RV"  // лучевая скорость и скорость вращения
+	BSCNoteSpectra             BSCNoteCategory = "S"   // спектр
+	BSCNoteSpectroscopicBinary BSCNoteCategory = "SB"  // спектроскопические двойные
+	BSCNoteVariability         BSCNoteCategory = "VAR" // переменность
+)
+
+// BSCNote примечания к звезде по категориям.
+// Примечание, занимающее в файле несколько строк, объединяется в одну строку.
+type BSCNote map[BSCNoteCategory]string
 
-func (n BSCNote) add(key string, index uint, value string) {
-	if index > 1 {
-		n[key] += value
+func (n BSCNote) add(key BSCNoteCategory, index uint, value string) {
+	if index > 1 && n[key] != "" {
+		n[key] += " " + value
 		return
 	}
 	n[key] = value
 }
+
+// AttachBSCNotes добавляет примечания к записям каталога с соответствующими номерами HR.
+func AttachBSCNotes(records []*BSCRecord, notes map[uint]BSCNote) {
+	for _, record := range records {
+		if note, ok := notes[record.HR]; ok {
+			record.Notes = note
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package gorewind
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// bscNoteLine возвращает строку файла notes каталога V/50: HR в байтах 2-5, номер строки в 6-7,
+// категория в 8-11, текст с 13-го байта.
+func bscNoteLine(hr, count int, category, remark string) string {
+	return fmt.Sprintf("%5d%2d%-4s %s", hr, count, category, remark)
+}
+
+func TestBSCNotesReader(t *testing.T) {
+	data := strings.Join([]string{
+		bscNoteLine(15, 1, "N:", "Alpheratz, Sirrah"),
+		bscNoteLine(15, 2, "SB:", "Orbit by Pourbaix 2000."),
+		bscNoteLine(15, 3, "", "Period 96.7 days."),
+		bscNoteLine(1708, 1, "DYN:", "0.075"),
+		bscNoteLine(1708, 2, "VAR:", "Slightly variable."),
+	}, "\n") + "\n"
+
+	notes, err := NewBSCNotesReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		hr       uint
+		category BSCNoteCategory
+		want     string
+	}{
+		{15, BSCNoteNames, "Alpheratz, Sirrah"},
+		{15, BSCNoteSpectroscopicBinary, "Orbit by Pourbaix 2000. Period 96.7 days."},
+		{1708, BSCNoteDynamicalParallax, "0.075"},
+		{1708, BSCNoteVariability, "Slightly variable."},
+	}
+	for _, test := range tests {
+		if got := notes[test.hr][test.category]; got != test.want {
+			t.Errorf("HR %d %s: %q, want %q", test.hr, test.category, got, test.want)
+		}
+	}
+	if len(notes[15]) != 2 || len(notes[1708]) != 2 {
+		t.Errorf("categories: %v", notes)
+	}
+
+	records := []*BSCRecord{{HR: 15}, {HR: 16}}
+	AttachBSCNotes(records, notes)
+	if records[0].Notes[BSCNoteNames] != "Alpheratz, Sirrah" || records[1].Notes != nil {
+		t.Errorf("attached notes: %v, %v", records[0].Notes, records[1].Notes)
+	}
+}
+
+// Строки каталога V/50 (bsc5.dat): HR 1 и δ Ori (HR 1852) со склонением -00°17′57″.
+const (
+	testBSCLineHR1    = "   1          BD+44 4550      3 36042          46           000001.1+444022000509.9+451345114.44-16.88 6.70  +0.07 +0.08       A1Vn                 -0.012-0.018      -018      195  4.2  21.6AC   3"
+	testBSCLineHR1852 = "1852 34Del OriBD-00  983  36486132220 206                                  053200.4-001757203.86-17.74 2.23  -0.22 -1.05       O9.5II"
+)
+
+func TestBSCReaderRecord(t *testing.T) {
+	reader := NewBSCReader(strings.NewReader(testBSCLineHR1 + "\n" + testBSCLineHR1852 + "\n"))
+	hr1, err := reader.ReadRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltaOri, err := reader.ReadRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		record             *BSCRecord
+		hr, hd, sao        uint
+		coords             SphericalCoords
+		magnitude          float64
+		spectralType       string
+		bv, ub             float64
+		designation        Designation
+		properMotionDec    float64
+		rotationalVelocity float64
+		componentsID       string
+		componentsCount    uint
+		hasCoordsB1900     bool
+	}{
+		{
+			name: "HR 1", record: hr1, hr: 1, hd: 3, sao: 36042,
+			coords:    NewClockCoords(0, 5, 9.9, 45, 13, 45),
+			magnitude: 6.70, spectralType: "A1Vn", bv: 0.07, ub: 0.08,
+			properMotionDec: -0.018, rotationalVelocity: 195, componentsID: "AC", componentsCount: 3,
+			hasCoordsB1900: true,
+		},
+		{
+			name: "HR 1852", record: deltaOri, hr: 1852, hd: 36486, sao: 132220,
+			coords:    NewClockCoords(5, 32, 0.4, 0, -17, -57),
+			magnitude: 2.23, spectralType: "O9.5II", bv: -0.22, ub: -1.05,
+			designation:     Designation{FlamsteedCode: 34, BayerCode: 'δ', Constellation: "Ori"},
+			properMotionDec: math.NaN(), rotationalVelocity: math.NaN(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := test.record
+			if r.HR != test.hr || r.HD != test.hd || r.SAO != test.sao {
+				t.Errorf("HR %d, HD %d, SAO %d, want %d, %d, %d", r.HR, r.HD, r.SAO, test.hr, test.hd, test.sao)
+			}
+			if !r.HasCoords || math.Abs(r.Coords.Longitude.Radians()-test.coords.Longitude.Radians()) > 1e-12 ||
+				math.Abs(r.Coords.Latitude.Radians()-test.coords.Latitude.Radians()) > 1e-12 {
+				t.Errorf("coords %.6f°, %.6f°, want %.6f°, %.6f°", r.Coords.Longitude.Degrees(), r.Coords.Latitude.Degrees(),
+					test.coords.Longitude.Degrees(), test.coords.Latitude.Degrees())
+			}
+			if (r.Coords.Latitude.Radians() < 0) != (test.coords.Latitude.Radians() < 0) {
+				t.Errorf("declination sign: %v", r.Coords.Latitude.Degrees())
+			}
+			if r.VMagnitude != test.magnitude || r.SpectralType != test.spectralType || r.BV != test.bv || r.UB != test.ub {
+				t.Errorf("Vmag %v, SpType %q, B-V %v, U-B %v", r.VMagnitude, r.SpectralType, r.BV, r.UB)
+			}
+			if r.Designation != test.designation {
+				t.Errorf("designation %+v, want %+v", r.Designation, test.designation)
+			}
+			if !sameFloat(r.ProperMotionDec, test.properMotionDec) || !sameFloat(r.RotationalVelocity, test.rotationalVelocity) {
+				t.Errorf("pmDE %v, RotVel %v", r.ProperMotionDec, r.RotationalVelocity)
+			}
+			if r.ComponentsID != test.componentsID || r.ComponentsCount != test.componentsCount {
+				t.Errorf("components %q %d", r.ComponentsID, r.ComponentsCount)
+			}
+			if hasB1900 := r.CoordsB1900 != (SphericalCoords{}); hasB1900 != test.hasCoordsB1900 {
+				t.Errorf("B1900 coords present %v, want %v", hasB1900, test.hasCoordsB1900)
+			}
+		})
+	}
+
+	object := hr1.GetAstronomicalObject()
+	if object.Index != 1 || object.Magnitude != 6.70 || object.Motion.ProperMotionDec != -0.018 || object.Motion.Parallax != 0 {
+		t.Errorf("object %+v", object)
+	}
+}
+
+// sameFloat сравнивает числа, считая NaN равными.
+func sameFloat(a, b float64) bool {
+	return a == b || math.IsNaN(a) && math.IsNaN(b)
+}
+
+func TestNewClockCoordsNegativeZeroDegrees(t *testing.T) {
+	c := NewClockCoords(5, 32, 0.4, 0, -17, -57)
+	if want := -(17.0/60 + 57.0/3600); math.Abs(c.Latitude.Degrees()-want) > 1e-12 {
+		t.Errorf("latitude %v°, want %v°", c.Latitude.Degrees(), want)
+	}
+	c = NewClockCoords(5, 32, 0.4, -16, 42, 0)
+	if math.Abs(c.Latitude.Degrees()+16.7) > 1e-12 {
+		t.Errorf("latitude %v°, want -16.7°", c.Latitude.Degrees())
+	}
+}
+
+func TestBSCReaderParseError(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		field string
+		err   error
+	}{
+		{"bad HR", "  x1" + testBSCLineHR1[4:], "HR", strconv.ErrSyntax},
+		{"bad Vmag", testBSCLineHR1[:102] + "6.7x " + testBSCLineHR1[107:], "Vmag", strconv.ErrSyntax},
+		{"truncated coordinates", testBSCLineHR1[:80], "DEd", ErrEmptyField},
+	}
+	for _, test := range tests {
+		// ошибка во второй строке: первая строка читается без ошибок
+		reader := NewBSCReader(strings.NewReader(testBSCLineHR1852 + "\n" + test.line + "\n"))
+		if _, err := reader.ReadRecord(); err != nil {
+			t.Fatalf("%s: first record: %v", test.name, err)
+		}
+		_, err := reader.ReadRecord()
+		var parseError *ParseError
+		if !errors.As(err, &parseError) {
+			t.Errorf("%s: error %v, want *ParseError", test.name, err)
+			continue
+		}
+		if parseError.Catalogue != CatalogueBSC || parseError.Line != 2 || parseError.Field != test.field || !errors.Is(err, test.err) {
+			t.Errorf("%s: %s line %d field %s: %v, want line 2 field %s: %v",
+				test.name, parseError.Catalogue, parseError.Line, parseError.Field, parseError.Err, test.field, test.err)
+		}
+	}
+}
+
+func TestBSCReaderLenient(t *testing.T) {
+	data := strings.Join([]string{
+		testBSCLineHR1,
+		"  x1" + testBSCLineHR1[4:],
+		testBSCLineHR1[:14], // запись без координат
+		testBSCLineHR1[:80],
+		testBSCLineHR1852,
+	}, "\n")
+	reader := NewBSCReader(strings.NewReader(data))
+	reader.Lenient = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Index != 1 || records[1].Index != 1852 {
+		t.Fatalf("records %v, want HR 1 and HR 1852", records)
+	}
+	stats := reader.Stats()
+	if stats.Read != 2 || stats.Skipped != 1 || stats.Failed != 2 || len(stats.Errors) != 2 {
+		t.Fatalf("stats %+v, want 2 read, 1 skipped and 2 failed", stats)
+	}
+	if stats.Errors[0].Line != 2 || stats.Errors[0].Field != "HR" || stats.Errors[1].Line != 4 || stats.Errors[1].Field != "DEd" {
+		t.Errorf("errors %v, want HR at line 2 and DEd at line 4", stats.Errors)
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("after last record: %v, want io.EOF", err)
+	}
+}
@@ -1,11 +1,11 @@
 package gorewind
 
 import (
-	"encoding/csv"
+	"context"
 	"io"
-	"os"
-	"strconv"
+	"io/fs"
 	"strings"
+	"time"
 )
 
 // gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
@@ -18,63 +18,213 @@ import (
 // http://download.geonames.org/export/dump/
 
 func ReadCitiesCatalogue(path string) ([]*Location, error) {
-	file, err := os.Open(path)
+	records, _, err := ReadCitiesCatalogueWithOptions(path, ReadOptions{})
+	return records, err
+}
+
+// ReadCitiesCatalogueWithOptions читает каталог с параметрами options, например, в нестрогом режиме,
+// и возвращает статистику чтения.
+func ReadCitiesCatalogueWithOptions(path string, options ReadOptions) ([]*Location, ReadStats, error) {
+	file, err := OpenCatalogue(path, options.Member)
+	if err != nil {
+		return nil, ReadStats{}, err
+	}
+	defer file.Close()
+
+	reader := NewCitiesReader(file)
+	reader.ReadOptions = options
+	records, err := reader.ReadAll()
+	return records, setStatsErrorFile(reader.Stats(), path), setErrorFile(err, path)
+}
+
+// ReadCitiesCatalogueFS читает каталог из файловой системы fsys, например, из embed.FS.
+func ReadCitiesCatalogueFS(fsys fs.FS, name string) ([]*Location, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var result []*Location
-	csvReader := csv.NewReader(file)
-	csvReader.Comma = '\t'
+	records, err := NewCitiesReader(file).ReadAll()
+	return records, setErrorFile(err, name)
+}
+
+// geoNamesFields названия полей записи GeoNames.
+var geoNamesFields = []string{
+	"geonameid",
+	"name",
+	"asciiname",
+	"alternatenames",
+	"latitude",
+	"longitude",
+	"feature class",
+	"feature code",
+	"country code",
+	"cc2",
+	"admin1 code",
+	"admin2 code",
+	"admin3 code",
+	"admin4 code",
+	"population",
+	"elevation",
+	"dem",
+	"timezone",
+	"modification date",
+}
+
+// CitiesReader последовательно читает записи GeoNames.
+// Файлы GeoNames разделены табуляцией и не используют кавычки, поэтому строки разбираются без encoding/csv.
+type CitiesReader struct {
+	lineReader
+
+	// FeatureCodes если задано, читаются только объекты с перечисленными кодами, например, PPLC и PPLA.
+	// Остальные объекты учитываются в статистике как пропущенные.
+	FeatureCodes []string
+	// Language язык LocalName, ISO 639, например, "en"; пустая строка означает DefaultLanguage.
+	Language string
+	// Names если задано, LocalName выбирается по alternateNamesV2.txt,
+	// иначе — из альтернативных названий записи по письменности языка.
+	Names *NameResolver
+}
+
+// NewCitiesReader создаёт CitiesReader, читающий каталог из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewCitiesReader(r io.Reader) *CitiesReader {
+	return &CitiesReader{lineReader: lineReader{source: r}}
+}
 
+// Read возвращает следующую запись каталога или io.EOF, если записей больше нет.
+func (r *CitiesReader) Read() (*Location, error) {
 	for {
-		record, err := csvReader.Read()
+		line, err := r.readLine()
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
 			return nil, err
 		}
-		latitude, err := strconv.ParseFloat(record[4], 64)
+
+		location, err := getLocation(r.line, line)
 		if err != nil {
-			return nil, err
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
 		}
-		longitude, err := strconv.ParseFloat(record[5], 64)
-		if err != nil {
-			return nil, err
+		if !r.hasFeatureCode(location.FeatureCode) {
+			r.stats.Skipped++
+			continue
 		}
-		population, err := strconv.ParseUint(record[14], 10, 64)
-		if err != nil {
-			return nil, err
+		language := r.Language
+		if language == "" {
+			language = DefaultLanguage
 		}
-		location := Location{
-			Name:        record[1],
-			LocalName:   getLocalCityName(record[3]),
-			Population:  uint(population),
-			CountryCode: record[8],
-			Coords:      NewCoordsFromDegrees(longitude, latitude),
+		location.LocalName = r.Names.GetLocalName(location, language)
+		r.stats.Read++
+		return location, nil
+	}
+}
+
+func (r *CitiesReader) hasFeatureCode(code string) bool {
+	if len(r.FeatureCodes) == 0 {
+		return true
+	}
+	for _, featureCode := range r.FeatureCodes {
+		if featureCode == code {
+			return true
 		}
-		result = append(result, &location)
+	}
+	return false
+}
+
+// ReadAll читает все оставшиеся записи каталога.
+func (r *CitiesReader) ReadAll() ([]*Location, error) {
+	var result []*Location
+	err := r.Visit(context.Background(), func(location *Location) error {
+		result = append(result, location)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return result, nil
 }
 
-func getLocalCityName(s string) string {
-	fields := strings.Split(s, ",")
-	for _, field := range fields {
-		if isRussianText(field) {
-			return field
+// Visit вызывает fn для каждой оставшейся записи каталога.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *CitiesReader) Visit(ctx context.Context, fn func(*Location) error) error {
+	return visit(ctx, func() error {
+		location, err := r.Read()
+		if err != nil {
+			return err
 		}
-	}
-	return ""
+		return fn(location)
+	})
 }
 
-func isRussianText(text string) bool {
-	for _, r := range []rune(text) {
-		if (r < '\u0410' || r > '\u042F') && (r < '\u0430' || r > '\u044F') {
-			return false
+func getLocation(lineNumber int, line string) (*Location, error) {
+	record, err := newTabLine(CatalogueGeoNames, lineNumber, line, geoNamesFields)
+	if err != nil {
+		return nil, err
+	}
+	latitude, err := record.parseFloat(4)
+	if err != nil {
+		return nil, err
+	}
+	longitude, err := record.parseFloat(5)
+	if err != nil {
+		return nil, err
+	}
+	geoNameID, err := record.parseUint(0)
+	if err != nil {
+		return nil, err
+	}
+	population, err := record.parseUint(14)
+	if err != nil {
+		return nil, err
+	}
+	location := Location{
+		GeoNameID:             uint(geoNameID),
+		Name:                  record.fields[1],
+		ASCIIName:             record.fields[2],
+		AlternateNames:        splitList(record.fields[3]),
+		FeatureClass:          record.fields[6],
+		FeatureCode:           record.fields[7],
+		Population:            uint(population),
+		CountryCode:           record.fields[8],
+		AlternateCountryCodes: splitList(record.fields[9]),
+		Admin1Code:            record.fields[10],
+		Admin2Code:            record.fields[11],
+		Admin3Code:            record.fields[12],
+		Admin4Code:            record.fields[13],
+		TimeZone:              record.fields[17],
+		Coords:                NewCoordsFromDegrees(longitude, latitude),
+	}
+	if record.fields[15] != "" {
+		elevation, err := record.parseInt(15)
+		if err != nil {
+			return nil, err
+		}
+		location.Elevation = int(elevation)
+	}
+	if record.fields[16] != "" {
+		dem, err := record.parseInt(16)
+		if err != nil {
+			return nil, err
+		}
+		location.DEM = int(dem)
+	}
+	if record.fields[18] != "" {
+		date, err := time.Parse("2006-01-02", record.fields[18])
+		if err != nil {
+			return nil, record.error(18, err)
 		}
+		location.ModificationDate = date
+	}
+	return &location, nil
+}
+
+// splitList разбивает список, разделённый запятыми; для пустой строки возвращает nil.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
 	}
-	return true
+	return strings.Split(s, ",")
 }
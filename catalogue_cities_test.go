@@ -0,0 +1,53 @@
+package gorewind
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadCitiesCatalogueFSErrorFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cities15000.txt": &fstest.MapFile{Data: []byte("524901\tMoscow\n")},
+	}
+	_, err := ReadCitiesCatalogueFS(fsys, "cities15000.txt")
+	var parseError *ParseError
+	if !errors.As(err, &parseError) {
+		t.Fatalf("error %v, want *ParseError", err)
+	}
+	if parseError.File != "cities15000.txt" {
+		t.Errorf("File = %q, want cities15000.txt", parseError.File)
+	}
+	if !errors.Is(err, ErrFieldCount) {
+		t.Errorf("error %v, want ErrFieldCount", err)
+	}
+}
+
+func TestReadCitiesCatalogueWithOptionsLenient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities15000.txt")
+	data := "524901\tMoscow\tMoscow\tMoskva\t55.75222\t37.61556\tP\tPPLC\tRU\t\t48\t\t\t\t10381222\t\t144\tEurope/Moscow\t2022-12-10\n" +
+		"498817\tSaint Petersburg\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadCitiesCatalogue(path); !errors.Is(err, ErrFieldCount) {
+		t.Fatalf("strict error %v, want ErrFieldCount", err)
+	}
+
+	locations, stats, err := ReadCitiesCatalogueWithOptions(path, ReadOptions{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != 1 || locations[0].Name != "Moscow" {
+		t.Fatalf("locations %v, want Moscow", locations)
+	}
+	if stats.Read != 1 || stats.Failed != 1 || len(stats.Errors) != 1 {
+		t.Fatalf("stats %+v, want 1 read and 1 failed", stats)
+	}
+	if stats.Errors[0].File != path || stats.Errors[0].Line != 2 {
+		t.Errorf("error at %s:%d, want %s:2", stats.Errors[0].File, stats.Errors[0].Line, path)
+	}
+}
@@ -0,0 +1,277 @@
+package gorewind
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// Названия каталогов в ParseError.
+const (
+	CatalogueBSC            = "BSC"
+	CatalogueBSCNotes       = "BSC notes"
+	CatalogueNGC            = "NGC"
+	CatalogueNGCNames       = "NGC names"
+	CatalogueNames          = "astrocat"
+	CatalogueGeoNames       = "geonames"
+	CatalogueAlternateNames = "geonames alternate names"
+	CatalogueCountries      = "geonames countries"
+	CatalogueAdminDivisions = "geonames admin codes"
+	CatalogueTimeZones      = "geonames time zones"
+)
+
+var (
+	// ErrEmptyField обязательное поле записи каталога не заполнено или отсутствует в слишком короткой строке.
+	ErrEmptyField = errors.New("empty field")
+	// ErrFieldCount количество полей в строке каталога, разделённой табуляцией, не совпадает с ожидаемым.
+	ErrFieldCount = errors.New("wrong number of fields")
+)
+
+// ParseError ошибка разбора записи каталога.
+// Доступна через errors.As для ошибок, возвращаемых функциями чтения каталогов.
+type ParseError struct {
+	Catalogue string // название каталога, например, CatalogueBSC
+	File      string // путь к файлу, если каталог читается из файла
+	Line      int    // номер строки с 1; для значений полей Astro Catalogue — номер записи CSV
+	Column    int    // первый байт поля в строке с 1, 0 если неизвестен
+	EndColumn int    // последний байт поля в строке с 1, 0 если неизвестен
+	Field     string // название поля
+	Text      string // исходный текст поля
+	Err       error  // исходная ошибка
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Catalogue)
+	if e.File != "" {
+		b.WriteString(" " + e.File)
+	}
+	b.WriteString(":" + strconv.Itoa(e.Line))
+	if e.Column != 0 {
+		b.WriteString(":" + strconv.Itoa(e.Column))
+		if e.EndColumn > e.Column {
+			b.WriteString("-" + strconv.Itoa(e.EndColumn))
+		}
+	}
+	if e.Field != "" {
+		b.WriteString(": field " + e.Field)
+	}
+	if e.Text != "" {
+		b.WriteString(" " + strconv.Quote(e.Text))
+	}
+	b.WriteString(": " + e.Err.Error())
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// setErrorFile дополняет ошибку разбора путём к файлу.
+func setErrorFile(err error, path string) error {
+	var parseError *ParseError
+	if errors.As(err, &parseError) && parseError.File == "" {
+		parseError.File = path
+	}
+	return err
+}
+
+// setStatsErrorFile дополняет путём к файлу ошибки разбора, сохранённые в статистике чтения.
+func setStatsErrorFile(stats ReadStats, path string) ReadStats {
+	for _, parseError := range stats.Errors {
+		setErrorFile(parseError, path)
+	}
+	return stats
+}
+
+// fixedLine строка каталога с полями фиксированной ширины.
+// Позиции полей задаются номерами байтов с 1 включительно, как в описаниях каталогов CDS (ReadMe).
+type fixedLine struct {
+	catalogue string
+	line      int
+	s         string
+}
+
+// raw возвращает байты с start по end без обрезки пробелов; в короткой строке — только имеющуюся часть.
+func (l *fixedLine) raw(start, end int) string {
+	if start > len(l.s) {
+		return ""
+	}
+	if end > len(l.s) {
+		end = len(l.s)
+	}
+	return l.s[start-1 : end]
+}
+
+// text возвращает байты с start по end без пробелов по краям.
+func (l *fixedLine) text(start, end int) string {
+	return strings.TrimSpace(l.raw(start, end))
+}
+
+func (l *fixedLine) error(field string, start, end int, err error) *ParseError {
+	var numError *strconv.NumError
+	if errors.As(err, &numError) {
+		err = numError.Err
+	}
+	return &ParseError{
+		Catalogue: l.catalogue,
+		Line:      l.line,
+		Column:    start,
+		EndColumn: end,
+		Field:     field,
+		Text:      l.raw(start, end),
+		Err:       err,
+	}
+}
+
+func (l *fixedLine) parseUint(field string, start, end int) (uint64, error) {
+	s := l.text(start, end)
+	if s == "" {
+		return 0, l.error(field, start, end, ErrEmptyField)
+	}
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, l.error(field, start, end, err)
+	}
+	return value, nil
+}
+
+func (l *fixedLine) parseInt(field string, start, end int) (int64, error) {
+	s := l.text(start, end)
+	if s == "" {
+		return 0, l.error(field, start, end, ErrEmptyField)
+	}
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, l.error(field, start, end, err)
+	}
+	return value, nil
+}
+
+// optionalUint возвращает 0 для пустого поля.
+func (l *fixedLine) optionalUint(field string, start, end int) (uint64, error) {
+	if l.text(start, end) == "" {
+		return 0, nil
+	}
+	return l.parseUint(field, start, end)
+}
+
+// optionalFloat возвращает NaN для пустого поля.
+func (l *fixedLine) optionalFloat(field string, start, end int) (float64, error) {
+	if l.text(start, end) == "" {
+		return math.NaN(), nil
+	}
+	return l.parseFloat(field, start, end)
+}
+
+func (l *fixedLine) parseFloat(field string, start, end int) (float64, error) {
+	s := l.text(start, end)
+	if s == "" {
+		return 0, l.error(field, start, end, ErrEmptyField)
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, l.error(field, start, end, err)
+	}
+	return value, nil
+}
+
+// tabLine строка каталога с полями, разделёнными табуляцией.
+type tabLine struct {
+	catalogue string
+	line      int
+	fields    []string
+	names     []string // названия полей
+}
+
+func newTabLine(catalogue string, line int, s string, names []string) (*tabLine, error) {
+	l := tabLine{
+		catalogue: catalogue,
+		line:      line,
+		fields:    strings.Split(s, "\t"),
+		names:     names,
+	}
+	if len(l.fields) != len(names) {
+		return nil, &ParseError{
+			Catalogue: catalogue,
+			Line:      line,
+			Err:       ErrFieldCount,
+		}
+	}
+	return &l, nil
+}
+
+func (l *tabLine) error(index int, err error) *ParseError {
+	var numError *strconv.NumError
+	if errors.As(err, &numError) {
+		err = numError.Err
+	}
+	column := 1
+	for _, field := range l.fields[:index] {
+		column += len(field) + 1
+	}
+	return &ParseError{
+		Catalogue: l.catalogue,
+		Line:      l.line,
+		Column:    column,
+		EndColumn: column + len(l.fields[index]) - 1,
+		Field:     l.names[index],
+		Text:      l.fields[index],
+		Err:       err,
+	}
+}
+
+func (l *tabLine) parseUint(index int) (uint64, error) {
+	if l.fields[index] == "" {
+		return 0, l.error(index, ErrEmptyField)
+	}
+	value, err := strconv.ParseUint(l.fields[index], 10, 64)
+	if err != nil {
+		return 0, l.error(index, err)
+	}
+	return value, nil
+}
+
+func (l *tabLine) parseInt(index int) (int64, error) {
+	if l.fields[index] == "" {
+		return 0, l.error(index, ErrEmptyField)
+	}
+	value, err := strconv.ParseInt(l.fields[index], 10, 64)
+	if err != nil {
+		return 0, l.error(index, err)
+	}
+	return value, nil
+}
+
+// optionalUint возвращает 0 для пустого поля.
+func (l *tabLine) optionalUint(index int) (uint64, error) {
+	if l.fields[index] == "" {
+		return 0, nil
+	}
+	return l.parseUint(index)
+}
+
+// optionalFloat возвращает NaN для пустого поля.
+func (l *tabLine) optionalFloat(index int) (float64, error) {
+	if l.fields[index] == "" {
+		return math.NaN(), nil
+	}
+	return l.parseFloat(index)
+}
+
+func (l *tabLine) parseFloat(index int) (float64, error) {
+	if l.fields[index] == "" {
+		return 0, l.error(index, ErrEmptyField)
+	}
+	value, err := strconv.ParseFloat(l.fields[index], 64)
+	if err != nil {
+		return 0, l.error(index, err)
+	}
+	return value, nil
+}
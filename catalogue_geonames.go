@@ -0,0 +1,447 @@
+package gorewind
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// Вспомогательные файлы GeoNames: countryInfo.txt, admin1CodesASCII.txt, admin2Codes.txt и timeZones.txt.
+// http://download.geonames.org/export/dump/
+
+// ReadCountries читает список стран countryInfo.txt.
+func ReadCountries(path string) ([]*Country, error) {
+	file, err := OpenCatalogue(path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := NewCountriesReader(file).ReadAll()
+	return records, setErrorFile(err, path)
+}
+
+// ReadCountriesFS читает список стран из файловой системы fsys, например, из embed.FS.
+func ReadCountriesFS(fsys fs.FS, name string) ([]*Country, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := NewCountriesReader(file).ReadAll()
+	return records, setErrorFile(err, name)
+}
+
+// ReadAdminDivisions читает административные деления admin1CodesASCII.txt или admin2Codes.txt.
+func ReadAdminDivisions(path string) ([]*AdminDivision, error) {
+	file, err := OpenCatalogue(path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := NewAdminDivisionsReader(file).ReadAll()
+	return records, setErrorFile(err, path)
+}
+
+// ReadAdminDivisionsFS читает административные деления из файловой системы fsys, например, из embed.FS.
+func ReadAdminDivisionsFS(fsys fs.FS, name string) ([]*AdminDivision, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := NewAdminDivisionsReader(file).ReadAll()
+	return records, setErrorFile(err, name)
+}
+
+// ReadTimeZones читает часовые пояса timeZones.txt.
+func ReadTimeZones(path string) ([]*TimeZone, error) {
+	file, err := OpenCatalogue(path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := NewTimeZonesReader(file).ReadAll()
+	return records, setErrorFile(err, path)
+}
+
+// ReadTimeZonesFS читает часовые пояса из файловой системы fsys, например, из embed.FS.
+func ReadTimeZonesFS(fsys fs.FS, name string) ([]*TimeZone, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := NewTimeZonesReader(file).ReadAll()
+	return records, setErrorFile(err, name)
+}
+
+// Country страна из countryInfo.txt.
+type Country struct {
+	ISO                string   // код ISO 3166-1 alpha-2, Location.CountryCode
+	ISO3               string   // код ISO 3166-1 alpha-3
+	ISONumeric         uint     // цифровой код ISO 3166-1
+	FIPS               string   // код FIPS 10-4
+	Name               string   // название на английском языке
+	Capital            string   // столица
+	Area               float64  // площадь в квадратных километрах, NaN если неизвестна
+	Population         uint     // население
+	Continent          string   // код континента: AF, AN, AS, EU, NA, OC, SA
+	TLD                string   // домен верхнего уровня, например, .ru
+	CurrencyCode       string   // код валюты ISO 4217
+	CurrencyName       string   // название валюты
+	Phone              string   // телефонный код
+	PostalCodeFormat   string   // формат почтового индекса
+	PostalCodeRegex    string   // регулярное выражение почтового индекса
+	Languages          []string // языки страны, например, ru и tt
+	GeoNameID          uint     // идентификатор GeoNames, 0 если неизвестен
+	Neighbours         []string // коды ISO соседних стран
+	EquivalentFIPSCode string
+}
+
+// countryFields названия полей записи countryInfo.txt.
+var countryFields = []string{
+	"ISO",
+	"ISO3",
+	"ISO-Numeric",
+	"fips",
+	"Country",
+	"Capital",
+	"Area(in sq km)",
+	"Population",
+	"Continent",
+	"tld",
+	"CurrencyCode",
+	"CurrencyName",
+	"Phone",
+	"Postal Code Format",
+	"Postal Code Regex",
+	"Languages",
+	"geonameid",
+	"neighbours",
+	"EquivalentFipsCode",
+}
+
+// CountriesReader последовательно читает записи countryInfo.txt, пропуская комментарии.
+type CountriesReader struct {
+	lineReader
+}
+
+// NewCountriesReader создаёт CountriesReader, читающий файл из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewCountriesReader(r io.Reader) *CountriesReader {
+	return &CountriesReader{lineReader: lineReader{source: r}}
+}
+
+// Read возвращает следующую запись или io.EOF, если записей больше нет.
+func (r *CountriesReader) Read() (*Country, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if isGeoNamesComment(line) {
+			continue
+		}
+
+		country, err := getCountry(r.line, line)
+		if err != nil {
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		r.stats.Read++
+		return country, nil
+	}
+}
+
+// ReadAll читает все оставшиеся записи.
+func (r *CountriesReader) ReadAll() ([]*Country, error) {
+	var result []*Country
+	err := r.Visit(context.Background(), func(country *Country) error {
+		result = append(result, country)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Visit вызывает fn для каждой оставшейся записи.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *CountriesReader) Visit(ctx context.Context, fn func(*Country) error) error {
+	return visit(ctx, func() error {
+		country, err := r.Read()
+		if err != nil {
+			return err
+		}
+		return fn(country)
+	})
+}
+
+func getCountry(lineNumber int, line string) (*Country, error) {
+	record, err := newTabLine(CatalogueCountries, lineNumber, line, countryFields)
+	if err != nil {
+		return nil, err
+	}
+	if record.fields[0] == "" {
+		return nil, record.error(0, ErrEmptyField)
+	}
+	isoNumeric, err := record.optionalUint(2)
+	if err != nil {
+		return nil, err
+	}
+	area, err := record.optionalFloat(6)
+	if err != nil {
+		return nil, err
+	}
+	population, err := record.optionalUint(7)
+	if err != nil {
+		return nil, err
+	}
+	geoNameID, err := record.optionalUint(16)
+	if err != nil {
+		return nil, err
+	}
+	return &Country{
+		ISO:                record.fields[0],
+		ISO3:               record.fields[1],
+		ISONumeric:         uint(isoNumeric),
+		FIPS:               record.fields[3],
+		Name:               record.fields[4],
+		Capital:            record.fields[5],
+		Area:               area,
+		Population:         uint(population),
+		Continent:          record.fields[8],
+		TLD:                record.fields[9],
+		CurrencyCode:       record.fields[10],
+		CurrencyName:       record.fields[11],
+		Phone:              record.fields[12],
+		PostalCodeFormat:   record.fields[13],
+		PostalCodeRegex:    record.fields[14],
+		Languages:          splitList(record.fields[15]),
+		GeoNameID:          uint(geoNameID),
+		Neighbours:         splitList(record.fields[17]),
+		EquivalentFIPSCode: record.fields[18],
+	}, nil
+}
+
+// AdminDivision административное деление из admin1CodesASCII.txt или admin2Codes.txt.
+type AdminDivision struct {
+	Code      string // код вида RU.73 для первого уровня и RU.73.1234567 для второго
+	Name      string // название в UTF-8
+	ASCIIName string // название в ASCII
+	GeoNameID uint   // идентификатор GeoNames
+}
+
+// adminDivisionFields названия полей записи admin1CodesASCII.txt и admin2Codes.txt.
+var adminDivisionFields = []string{
+	"code",
+	"name",
+	"asciiname",
+	"geonameid",
+}
+
+// AdminDivisionsReader последовательно читает записи admin1CodesASCII.txt или admin2Codes.txt.
+type AdminDivisionsReader struct {
+	lineReader
+}
+
+// NewAdminDivisionsReader создаёт AdminDivisionsReader, читающий файл из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewAdminDivisionsReader(r io.Reader) *AdminDivisionsReader {
+	return &AdminDivisionsReader{lineReader: lineReader{source: r}}
+}
+
+// Read возвращает следующую запись или io.EOF, если записей больше нет.
+func (r *AdminDivisionsReader) Read() (*AdminDivision, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if isGeoNamesComment(line) {
+			continue
+		}
+
+		division, err := getAdminDivision(r.line, line)
+		if err != nil {
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		r.stats.Read++
+		return division, nil
+	}
+}
+
+// ReadAll читает все оставшиеся записи.
+func (r *AdminDivisionsReader) ReadAll() ([]*AdminDivision, error) {
+	var result []*AdminDivision
+	err := r.Visit(context.Background(), func(division *AdminDivision) error {
+		result = append(result, division)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Visit вызывает fn для каждой оставшейся записи.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *AdminDivisionsReader) Visit(ctx context.Context, fn func(*AdminDivision) error) error {
+	return visit(ctx, func() error {
+		division, err := r.Read()
+		if err != nil {
+			return err
+		}
+		return fn(division)
+	})
+}
+
+func getAdminDivision(lineNumber int, line string) (*AdminDivision, error) {
+	record, err := newTabLine(CatalogueAdminDivisions, lineNumber, line, adminDivisionFields)
+	if err != nil {
+		return nil, err
+	}
+	if record.fields[0] == "" {
+		return nil, record.error(0, ErrEmptyField)
+	}
+	geoNameID, err := record.parseUint(3)
+	if err != nil {
+		return nil, err
+	}
+	return &AdminDivision{
+		Code:      record.fields[0],
+		Name:      record.fields[1],
+		ASCIIName: record.fields[2],
+		GeoNameID: uint(geoNameID),
+	}, nil
+}
+
+// TimeZone часовой пояс из timeZones.txt.
+// Смещения от UTC указаны в часах на даты, указанные в заголовке файла.
+type TimeZone struct {
+	CountryCode string  // код страны ISO 3166-1 alpha-2
+	ID          string  // идентификатор часового пояса IANA, Location.TimeZone
+	GMTOffset   float64 // смещение 1 января
+	DSTOffset   float64 // смещение 1 июля
+	RawOffset   float64 // смещение без учёта летнего времени
+}
+
+// timeZoneFields названия полей записи timeZones.txt.
+var timeZoneFields = []string{
+	"CountryCode",
+	"TimeZoneId",
+	"GMT offset",
+	"DST offset",
+	"rawOffset",
+}
+
+// TimeZonesReader последовательно читает записи timeZones.txt, пропуская заголовок.
+type TimeZonesReader struct {
+	lineReader
+}
+
+// NewTimeZonesReader создаёт TimeZonesReader, читающий файл из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewTimeZonesReader(r io.Reader) *TimeZonesReader {
+	return &TimeZonesReader{lineReader: lineReader{source: r}}
+}
+
+// Read возвращает следующую запись или io.EOF, если записей больше нет.
+func (r *TimeZonesReader) Read() (*TimeZone, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if isGeoNamesComment(line) || r.line == 1 && strings.HasPrefix(line, "CountryCode\t") {
+			continue
+		}
+
+		timeZone, err := getTimeZone(r.line, line)
+		if err != nil {
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		r.stats.Read++
+		return timeZone, nil
+	}
+}
+
+// ReadAll читает все оставшиеся записи.
+func (r *TimeZonesReader) ReadAll() ([]*TimeZone, error) {
+	var result []*TimeZone
+	err := r.Visit(context.Background(), func(timeZone *TimeZone) error {
+		result = append(result, timeZone)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Visit вызывает fn для каждой оставшейся записи.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *TimeZonesReader) Visit(ctx context.Context, fn func(*TimeZone) error) error {
+	return visit(ctx, func() error {
+		timeZone, err := r.Read()
+		if err != nil {
+			return err
+		}
+		return fn(timeZone)
+	})
+}
+
+func getTimeZone(lineNumber int, line string) (*TimeZone, error) {
+	record, err := newTabLine(CatalogueTimeZones, lineNumber, line, timeZoneFields)
+	if err != nil {
+		return nil, err
+	}
+	if record.fields[1] == "" {
+		return nil, record.error(1, ErrEmptyField)
+	}
+	gmtOffset, err := record.parseFloat(2)
+	if err != nil {
+		return nil, err
+	}
+	dstOffset, err := record.parseFloat(3)
+	if err != nil {
+		return nil, err
+	}
+	rawOffset, err := record.parseFloat(4)
+	if err != nil {
+		return nil, err
+	}
+	return &TimeZone{
+		CountryCode: record.fields[0],
+		ID:          record.fields[1],
+		GMTOffset:   gmtOffset,
+		DSTOffset:   dstOffset,
+		RawOffset:   rawOffset,
+	}, nil
+}
+
+// isGeoNamesComment проверяет, что строка пуста или является комментарием, как заголовок countryInfo.txt.
+func isGeoNamesComment(line string) bool {
+	return line == "" || strings.HasPrefix(line, "#")
+}
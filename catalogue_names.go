@@ -1,10 +1,11 @@
 package gorewind
 
 import (
+	"context"
 	"encoding/csv"
 	"errors"
 	"io"
-	"os"
+	"io/fs"
 	"strconv"
 	"strings"
 )
@@ -18,36 +19,155 @@ import (
 // https://github.com/dvoeglazyi/astrocat
 
 func ReadNamesCatalogue(path string) ([]*AstronomicalObject, error) {
-	file, err := os.Open(path)
+	records, _, err := ReadNamesCatalogueWithOptions(path, ReadOptions{})
+	return records, err
+}
+
+// ReadNamesCatalogueWithOptions читает каталог с параметрами options, например, в нестрогом режиме,
+// и возвращает статистику чтения.
+func ReadNamesCatalogueWithOptions(path string, options ReadOptions) ([]*AstronomicalObject, ReadStats, error) {
+	file, err := OpenCatalogue(path, options.Member)
+	if err != nil {
+		return nil, ReadStats{}, err
+	}
+	defer file.Close()
+
+	reader := NewNamesReader(file)
+	reader.ReadOptions = options
+	records, err := reader.ReadAll()
+	return records, setStatsErrorFile(reader.Stats(), path), setErrorFile(err, path)
+}
+
+// ReadNamesCatalogueFS читает каталог из файловой системы fsys, например, из embed.FS.
+func ReadNamesCatalogueFS(fsys fs.FS, name string) ([]*AstronomicalObject, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	csvReader := csv.NewReader(file)
-	csvReader.Comma = ','
-	csvReader.FieldsPerRecord = 11
+	records, err := NewNamesReader(file).ReadAll()
+	return records, setErrorFile(err, name)
+}
+
+// NamesReader последовательно читает записи Astro Catalogue.
+type NamesReader struct {
+	readerState
+
+	source    io.Reader
+	csvReader *csv.Reader
+	record    int
+}
+
+// NewNamesReader создаёт NamesReader, читающий каталог из r.
+// Данные читаются как есть: сжатый файл нужно открыть через OpenCatalogue или обернуть r в Decompress.
+func NewNamesReader(r io.Reader) *NamesReader {
+	return &NamesReader{source: r}
+}
+
+// Read возвращает следующую запись каталога или io.EOF, если записей больше нет.
+func (r *NamesReader) Read() (*AstronomicalObject, error) {
+	if r.csvReader == nil {
+		r.csvReader = csv.NewReader(r.source)
+		r.csvReader.Comma = ','
+		r.csvReader.FieldsPerRecord = 11
+		r.csvReader.ReuseRecord = true
+	}
 
-	var result []*AstronomicalObject
 	for {
-		fields, err := csvReader.Read()
+		record, err := r.readRecord()
 		if err != nil {
-			if err == io.EOF {
-				break
+			if err = r.handleError(err); err != nil {
+				return nil, err
 			}
-			return nil, err
+			continue
 		}
+		r.stats.Read++
+		return record, nil
+	}
+}
 
-		record, err := readNamesCatalogueRecord(fields)
-		if err != nil {
-			return nil, err
+func (r *NamesReader) readRecord() (*AstronomicalObject, error) {
+	fields, err := r.csvReader.Read()
+	if err != nil {
+		var csvError *csv.ParseError
+		if errors.As(err, &csvError) {
+			r.record++
+			return nil, &ParseError{
+				Catalogue: CatalogueNames,
+				Line:      csvError.Line,
+				Column:    csvError.Column,
+				Err:       csvError.Err,
+			}
 		}
+		return nil, err
+	}
+	r.record++
 
+	record, err := readNamesCatalogueRecord(fields)
+	if err != nil {
+		var parseError *ParseError
+		if errors.As(err, &parseError) {
+			parseError.Line = r.record
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// ReadAll читает все оставшиеся записи каталога.
+func (r *NamesReader) ReadAll() ([]*AstronomicalObject, error) {
+	var result []*AstronomicalObject
+	err := r.Visit(context.Background(), func(record *AstronomicalObject) error {
 		result = append(result, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return result, nil
 }
 
+// Visit вызывает fn для каждой оставшейся записи каталога.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *NamesReader) Visit(ctx context.Context, fn func(*AstronomicalObject) error) error {
+	return visit(ctx, func() error {
+		record, err := r.Read()
+		if err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
+
+// namesCatalogueFields названия полей Astro Catalogue.
+var namesCatalogueFields = [...]string{
+	"name",
+	"local name",
+	"code",
+	"constellation",
+	"in-system index",
+	"catalogue index",
+	"magnitude",
+	"longitude",
+	"latitude",
+	"radius",
+	"alternate names",
+}
+
+func getNamesCatalogueError(fields []string, index int, err error) *ParseError {
+	var numError *strconv.NumError
+	if errors.As(err, &numError) {
+		err = numError.Err
+	}
+	return &ParseError{
+		Catalogue: CatalogueNames,
+		Field:     namesCatalogueFields[index],
+		Text:      fields[index],
+		Err:       err,
+	}
+}
+
 func readNamesCatalogueRecord(fields []string) (*AstronomicalObject, error) {
 	record := AstronomicalObject{
 		Name:      fields[0],
@@ -58,49 +178,49 @@ func readNamesCatalogueRecord(fields []string) (*AstronomicalObject, error) {
 		AlternateNames: strings.Split(fields[10], ";"),
 	}
 	if err := record.Designation.SetCode(fields[2]); err != nil {
-		return nil, err
+		return nil, getNamesCatalogueError(fields, 2, err)
 	}
 	if fields[4] != "" {
 		inSystemIndex, err := strconv.ParseUint(fields[4], 10, 64)
 		if err != nil {
-			return nil, err
+			return nil, getNamesCatalogueError(fields, 4, err)
 		}
 		record.Designation.InSystemIndex = uint(inSystemIndex)
 	}
 	if fields[5] != "" {
 		split := strings.Split(fields[5], " ")
 		if len(split) < 2 {
-			return nil, errors.New("invalid catalogue index")
+			return nil, getNamesCatalogueError(fields, 5, errors.New("invalid catalogue index"))
 		}
 		record.Catalogue = split[0]
 		index, err := strconv.ParseUint(split[1], 10, 64)
 		if err != nil {
-			return nil, err
+			return nil, getNamesCatalogueError(fields, 5, err)
 		}
 		record.Index = uint(index)
 	}
 	if fields[6] != "" {
 		magnitude, err := strconv.ParseFloat(fields[6], 64)
 		if err != nil {
-			return nil, err
+			return nil, getNamesCatalogueError(fields, 6, err)
 		}
 		record.Magnitude = magnitude
 	}
 	if fields[7] != "" && fields[8] != "" {
 		longitude, err := strconv.ParseFloat(fields[7], 64)
 		if err != nil {
-			return nil, err
+			return nil, getNamesCatalogueError(fields, 7, err)
 		}
 		latitude, err := strconv.ParseFloat(fields[8], 64)
 		if err != nil {
-			return nil, err
+			return nil, getNamesCatalogueError(fields, 8, err)
 		}
 		record.Coords = NewCoordsFromDegrees(longitude, latitude)
 	}
 	if fields[9] != "" {
 		radius, err := strconv.ParseUint(fields[9], 10, 64)
 		if err != nil {
-			return nil, err
+			return nil, getNamesCatalogueError(fields, 9, err)
 		}
 		record.Coords.Radius = float64(radius)
 	}
@@ -1,9 +1,11 @@
 package gorewind
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"io"
-	"os"
+	"io/fs"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -17,110 +19,364 @@ import (
 // https://cdsarc.unistra.fr/viz-bin/cat/VII/118
 
 func ReadNGCCatalogue(path, namesPath string) ([]*AstronomicalObject, error) {
-	namesMap, err := readNGCNames(namesPath)
+	records, _, err := ReadNGCCatalogueWithOptions(path, namesPath, ReadOptions{})
+	return records, err
+}
+
+// ReadNGCCatalogueWithOptions читает каталог и названия объектов с параметрами options, например,
+// в нестрогом режиме, и возвращает статистику чтения. ReadOptions.Member относится к файлу каталога.
+func ReadNGCCatalogueWithOptions(path, namesPath string, options ReadOptions) ([]*AstronomicalObject, ReadStats, error) {
+	file, err := OpenCatalogue(path, options.Member)
 	if err != nil {
-		return nil, err
+		return nil, ReadStats{}, err
+	}
+	defer file.Close()
+
+	var names io.Reader
+	if namesPath != "" {
+		namesFile, err := OpenCatalogue(namesPath, "")
+		if err != nil {
+			return nil, ReadStats{}, err
+		}
+		defer namesFile.Close()
+		names = namesFile
+	}
+
+	reader := NewNGCReader(file, names)
+	reader.ReadOptions = options
+	records, err := reader.ReadAll()
+	stats := reader.Stats()
+	for _, parseError := range stats.Errors {
+		setNGCErrorFile(parseError, path, namesPath)
 	}
+	return records, stats, setNGCErrorFile(err, path, namesPath)
+}
 
-	file, err := os.Open(path)
+// ReadNGCCatalogueFS читает каталог и названия объектов из файловой системы fsys, например, из embed.FS.
+func ReadNGCCatalogueFS(fsys fs.FS, name, namesName string) ([]*AstronomicalObject, error) {
+	file, err := OpenCatalogueFS(fsys, name, "")
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var records []*AstronomicalObject
-	reader := bufio.NewReader(file)
-	for {
-		line, err := reader.ReadString('\n')
+	var names io.Reader
+	if namesName != "" {
+		namesFile, err := OpenCatalogueFS(fsys, namesName, "")
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
 			return nil, err
 		}
+		defer namesFile.Close()
+		names = namesFile
+	}
 
-		record, err := getNGCRecord(line)
+	records, err := NewNGCReader(file, names).ReadAll()
+	return records, setNGCErrorFile(err, name, namesName)
+}
+
+// setNGCErrorFile дополняет ошибку разбора путём к файлу каталога или файлу названий.
+func setNGCErrorFile(err error, path, namesPath string) error {
+	var parseError *ParseError
+	if errors.As(err, &parseError) && parseError.Catalogue == CatalogueNGCNames {
+		return setErrorFile(err, namesPath)
+	}
+	return setErrorFile(err, path)
+}
+
+// NGCReader последовательно читает записи каталога NGC/IC.
+type NGCReader struct {
+	lineReader
+	names    io.Reader
+	namesMap map[ngcKey][]string
+}
+
+// NewNGCReader создаёт NGCReader, читающий каталог из r и названия объектов из names.
+// Файл названий (names.dat) читается целиком при первом чтении записи; names может быть nil.
+// Оба потока читаются как есть: сжатые файлы нужно открыть через OpenCatalogue или обернуть в Decompress.
+func NewNGCReader(r, names io.Reader) *NGCReader {
+	return &NGCReader{
+		lineReader: lineReader{source: r},
+		names:      names,
+	}
+}
+
+// Read возвращает следующую запись каталога или io.EOF, если записей больше нет.
+func (r *NGCReader) Read() (*AstronomicalObject, error) {
+	record, err := r.ReadRecord()
+	if err != nil {
+		return nil, err
+	}
+	return record.GetAstronomicalObject(), nil
+}
+
+// ReadRecord возвращает следующую полную запись каталога или io.EOF, если записей больше нет.
+func (r *NGCReader) ReadRecord() (*NGCRecord, error) {
+	if r.namesMap == nil {
+		r.namesMap = make(map[ngcKey][]string)
+		if r.names != nil {
+			if err := readNGCNames(r.names, r.namesMap, r.handleError); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for {
+		line, err := r.readLine()
 		if err != nil {
 			return nil, err
 		}
-		if names := namesMap[ngcKey{catalogue: record.Catalogue, index: record.Index}]; len(names) > 0 {
-			record.Name = names[0]
-			record.AlternateNames = names[1:]
+
+		record, err := getNGCRecord(&fixedLine{catalogue: CatalogueNGC, line: r.line, s: line})
+		if err != nil {
+			if err = r.handleError(err); err != nil {
+				return nil, err
+			}
+			continue
 		}
+		record.Names = r.namesMap[ngcKey{catalogue: record.Catalogue, index: record.Index}]
+		r.stats.Read++
+		return record, nil
+	}
+}
+
+// ReadAll читает все оставшиеся записи каталога.
+func (r *NGCReader) ReadAll() ([]*AstronomicalObject, error) {
+	var records []*AstronomicalObject
+	err := r.Visit(context.Background(), func(record *AstronomicalObject) error {
 		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return records, nil
 }
 
-func getNGCRecord(s string) (*AstronomicalObject, error) {
-	longitudeHours, err := strconv.ParseUint(strings.TrimSpace(s[10:12]), 10, 64)
+// Visit вызывает fn для каждой оставшейся записи каталога.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *NGCReader) Visit(ctx context.Context, fn func(*AstronomicalObject) error) error {
+	return visit(ctx, func() error {
+		record, err := r.Read()
+		if err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
+
+// VisitRecords вызывает fn для каждой оставшейся полной записи каталога.
+// Чтение прекращается при отмене ctx или ошибке fn; ErrStop останавливает чтение без ошибки.
+func (r *NGCReader) VisitRecords(ctx context.Context, fn func(*NGCRecord) error) error {
+	return visit(ctx, func() error {
+		record, err := r.ReadRecord()
+		if err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
+
+// NGCObjectType класс объекта NGC 2000.0.
+type NGCObjectType int
+
+// Классы объектов NGC 2000.0.
+const (
+	NGCUnidentified      NGCObjectType = iota // не идентифицирован на фотографиях Паломарского обзора
+	NGCGalaxy                                 // Gx, галактика
+	NGCOpenCluster                            // OC, рассеянное скопление
+	NGCGlobularCluster                        // Gb, шаровое скопление
+	NGCNebula                                 // Nb, яркая эмиссионная или отражательная туманность
+	NGCPlanetaryNebula                        // Pl, планетарная туманность
+	NGCClusterWithNebula                      // C+N, скопление с туманностью
+	NGCAsterism                               // Ast, астеризм или группа нескольких звёзд
+	NGCKnot                                   // Kt, узел или туманность в другой галактике
+	NGCTripleStar                             // ***, тройная звезда
+	NGCDoubleStar                             // D*, двойная звезда
+	NGCStar                                   // *, одиночная звезда
+	NGCUncertain                              // ?, класс не определён
+	NGCNonexistent                            // -, объект отсутствует в RNGC
+	NGCPlateDefect                            // PD, дефект фотопластинки
+)
+
+var ngcObjectTypeCodes = [...]string{
+	NGCUnidentified:      "",
+	NGCGalaxy:            "Gx",
+	NGCOpenCluster:       "OC",
+	NGCGlobularCluster:   "Gb",
+	NGCNebula:            "Nb",
+	NGCPlanetaryNebula:   "Pl",
+	NGCClusterWithNebula: "C+N",
+	NGCAsterism:          "Ast",
+	NGCKnot:              "Kt",
+	NGCTripleStar:        "***",
+	NGCDoubleStar:        "D*",
+	NGCStar:              "*",
+	NGCUncertain:         "?",
+	NGCNonexistent:       "-",
+	NGCPlateDefect:       "PD",
+}
+
+// GetNGCObjectType возвращает класс объекта по его коду в каталоге.
+func GetNGCObjectType(code string) (NGCObjectType, bool) {
+	for t, c := range ngcObjectTypeCodes {
+		if c == code {
+			return NGCObjectType(t), true
+		}
+	}
+	return NGCUnidentified, false
+}
+
+// String возвращает код класса объекта в каталоге.
+func (t NGCObjectType) String() string {
+	if t < 0 || int(t) >= len(ngcObjectTypeCodes) {
+		return "NGCObjectType(" + strconv.Itoa(int(t)) + ")"
+	}
+	return ngcObjectTypeCodes[t]
+}
+
+// IsCluster возвращает true для рассеянных и шаровых скоплений, в том числе с туманностью.
+func (t NGCObjectType) IsCluster() bool {
+	return t == NGCOpenCluster || t == NGCGlobularCluster || t == NGCClusterWithNebula
+}
+
+// IsNebula возвращает true для туманностей, в том числе планетарных и окружающих скопления.
+func (t NGCObjectType) IsNebula() bool {
+	return t == NGCNebula || t == NGCPlanetaryNebula || t == NGCClusterWithNebula
+}
+
+// IsStellar возвращает true для одиночных, двойных и тройных звёзд и астеризмов.
+func (t NGCObjectType) IsStellar() bool {
+	return t == NGCStar || t == NGCDoubleStar || t == NGCTripleStar || t == NGCAsterism
+}
+
+// NGCRecord запись New General Catalogue and Index Catalogue, NGC 2000.0 (VII/118).
+// Отсутствующие в каталоге размер и звёздная величина равны NaN.
+type NGCRecord struct {
+	Catalogue     string          // NGC или IC
+	Index         uint            // номер в каталоге
+	Type          NGCObjectType   // класс объекта
+	Coords        SphericalCoords // равноденствие J2000
+	Source        string          // код источника данных о положении и классе объекта
+	Constellation string          // созвездие
+	SizeLimit     bool            // размер является верхним пределом ("<")
+	Size          float64         // наибольший угловой размер в угловых минутах
+	Magnitude     float64         // интегральная звёздная величина
+	Photographic  bool            // звёздная величина фотографическая, а не визуальная
+	Description   string          // описание в сокращениях Дрейера, например, "vF, S, R, gbM"
+	Names         []string        // названия из файла names.dat
+}
+
+func (r *NGCRecord) GetCoords() SphericalCoords {
+	return r.Coords
+}
+
+// GetConstellation возвращает сокращённое название созвездия, например, And.
+func (r *NGCRecord) GetConstellation() string {
+	return r.Constellation
+}
+
+// GetAstronomicalObject возвращает небесный объект, соответствующий записи каталога.
+func (r *NGCRecord) GetAstronomicalObject() *AstronomicalObject {
+	result := AstronomicalObject{
+		Catalogue: r.Catalogue,
+		Index:     r.Index,
+		Designation: Designation{
+			Constellation: r.Constellation,
+		},
+		Coords: r.Coords,
+	}
+	if !math.IsNaN(r.Magnitude) {
+		result.Magnitude = r.Magnitude
+	}
+	if len(r.Names) > 0 {
+		result.Name = r.Names[0]
+		result.AlternateNames = r.Names[1:]
+	}
+	return &result
+}
+
+func getNGCRecord(l *fixedLine) (*NGCRecord, error) {
+	key, err := getNGCKey(l, "Name", 1, 5)
 	if err != nil {
 		return nil, err
 	}
-	longitudeMinutes, err := strconv.ParseFloat(strings.TrimSpace(s[13:17]), 64)
+	objectType, ok := GetNGCObjectType(l.text(7, 9))
+	if !ok {
+		return nil, l.error("Type", 7, 9, errors.New("unknown object type"))
+	}
+
+	longitudeHours, err := l.parseUint("RAh", 11, 12)
 	if err != nil {
 		return nil, err
 	}
-
-	latitudeDegrees, err := strconv.ParseInt(strings.TrimSpace(s[19:22]), 10, 64)
+	longitudeMinutes, err := l.parseFloat("RAm", 14, 17)
 	if err != nil {
 		return nil, err
 	}
-	latitudeMinutes, err := strconv.ParseUint(strings.TrimSpace(s[23:25]), 10, 64)
+	latitudeDegrees, err := l.parseUint("DEd", 21, 22)
 	if err != nil {
 		return nil, err
 	}
-
-	var magnitude float64
-	if magnitudeString := strings.TrimSpace(s[40:44]); magnitudeString != "" {
-		if magnitude, err = strconv.ParseFloat(magnitudeString, 64); err != nil {
-			return nil, err
-		}
+	latitudeMinutes, err := l.parseUint("DEm", 24, 25)
+	if err != nil {
+		return nil, err
+	}
+	// the sign is stored separately, so declinations from -1 to 0 degrees keep it
+	latitude := float64(latitudeDegrees) + float64(latitudeMinutes)/60
+	if l.raw(20, 20) == "-" {
+		latitude = -latitude
 	}
 
-	key, err := getNGCKey(s[:5])
+	size, err := l.optionalFloat("size", 34, 38)
 	if err != nil {
 		return nil, err
 	}
-
-	return &AstronomicalObject{
-		Catalogue: key.catalogue,
-		Index:     key.index,
-		Designation: Designation{
-			Constellation: strings.TrimSpace(s[29:32]),
-		},
-		Magnitude: magnitude,
-		Coords:    NewClockCoords(uint(longitudeHours), longitudeMinutes, 0, int(latitudeDegrees), float64(latitudeMinutes), 0),
-	}, nil
-}
-
-func readNGCNames(path string) (map[ngcKey][]string, error) {
-	namesFile, err := os.Open(path)
+	magnitude, err := l.optionalFloat("mag", 41, 44)
 	if err != nil {
 		return nil, err
 	}
-	defer namesFile.Close()
 
-	namesMap := make(map[ngcKey][]string)
-	reader := bufio.NewReader(namesFile)
+	return &NGCRecord{
+		Catalogue:     key.catalogue,
+		Index:         key.index,
+		Type:          objectType,
+		Coords:        NewCoordsFromDegrees(15*(float64(longitudeHours)+longitudeMinutes/60), latitude),
+		Source:        l.text(27, 27),
+		Constellation: l.text(30, 32),
+		SizeLimit:     l.text(33, 33) == "<",
+		Size:          size,
+		Magnitude:     magnitude,
+		Photographic:  l.text(45, 45) == "p",
+		Description:   l.text(47, 96),
+	}, nil
+}
+
+func readNGCNames(r io.Reader, namesMap map[ngcKey][]string, handleError func(error) error) error {
+	reader := lineReader{source: r}
 	for {
-		s, err := reader.ReadString('\n')
+		s, err := reader.readLine()
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return nil, err
-		} else if len(s) < 41 {
+			return err
+		}
+		l := fixedLine{catalogue: CatalogueNGCNames, line: reader.line, s: s}
+		if l.text(37, 41) == "" {
+			// some names have no NGC or IC number
 			continue
 		}
-		key, err := getNGCKey(s[36:41])
+		key, err := getNGCKey(&l, "Name", 37, 41)
 		if err != nil {
-			return nil, err
+			if err = handleError(err); err != nil {
+				return err
+			}
+			continue
 		}
-		name := strings.TrimSpace(s[:35])
+		name := l.text(1, 35)
 		namesMap[key] = append(namesMap[key], name)
 	}
-	return namesMap, nil
+	return nil
 }
 
 type ngcKey struct {
@@ -128,18 +384,363 @@ type ngcKey struct {
 	index     uint
 }
 
-func getNGCKey(s string) (ngcKey, error) {
-	var key ngcKey
-	if s[0] == 'I' {
+func getNGCKey(l *fixedLine, field string, start, end int) (ngcKey, error) {
+	key := ngcKey{catalogue: "NGC"}
+	if l.text(start, start) == "I" {
 		key.catalogue = "IC"
-		s = s[1:]
-	} else {
-		key.catalogue = "NGC"
+		start++
 	}
-	index, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	index, err := l.parseUint(field, start, end)
 	if err != nil {
 		return key, err
 	}
 	key.index = uint(index)
 	return key, nil
 }
+
+// Описания объектов NGC записаны сокращениями Дрейера, например, "vF, S, R, gbM" —
+// very faint, small, round, gradually brighter in the middle.
+
+// NGCDescription расшифрованное описание объекта NGC.
+type NGCDescription struct {
+	Code      string // исходное описание
+	Name      string // описание на английском языке
+	LocalName string // описание на русском языке
+
+	Brightness   string // сокращение, описывающее яркость, например, "vF"
+	Size         string // сокращение, описывающее размер, например, "S"
+	Shape        string // сокращение, описывающее форму, например, "R" или "mE"
+	Condensation string // сокращение, описывающее концентрацию к центру, например, "gbM"
+	Remarkable   int    // степень примечательности объекта: количество "!" от 0 до 3
+}
+
+// GetDescription возвращает расшифрованное описание объекта.
+func (r *NGCRecord) GetDescription() NGCDescription {
+	return DecodeNGCDescription(r.Description)
+}
+
+// dreyerTerm расшифровка сокращения Дрейера.
+type dreyerTerm struct {
+	name      string
+	localName string
+}
+
+var dreyerTerms = map[string]dreyerTerm{
+	"ab":     {name: "about", localName: "около"},
+	"alm":    {name: "almost", localName: "почти"},
+	"am":     {name: "among", localName: "среди"},
+	"annul":  {name: "annular", localName: "кольцевая"},
+	"app":    {name: "appended", localName: "присоединённая"},
+	"att":    {name: "attached", localName: "прикреплённая"},
+	"b":      {name: "brighter", localName: "ярче"},
+	"bet":    {name: "between", localName: "между"},
+	"biN":    {name: "binuclear", localName: "с двумя ядрами"},
+	"bn":     {name: "brightest to north side", localName: "ярче к северу"},
+	"bs":     {name: "brightest to south side", localName: "ярче к югу"},
+	"bp":     {name: "brightest to preceding side", localName: "ярче к западу"},
+	"bf":     {name: "brightest to following side", localName: "ярче к востоку"},
+	"B":      {name: "bright", localName: "яркая"},
+	"c":      {name: "considerably", localName: "значительно"},
+	"C":      {name: "compressed", localName: "сжатая"},
+	"Cl":     {name: "cluster", localName: "скопление"},
+	"d":      {name: "diameter", localName: "диаметр"},
+	"def":    {name: "defined", localName: "очерченная"},
+	"dif":    {name: "diffused", localName: "диффузная"},
+	"diffic": {name: "difficult", localName: "трудная для наблюдения"},
+	"dist":   {name: "distant", localName: "на расстоянии"},
+	"D":      {name: "double", localName: "двойная"},
+	"e":      {name: "extremely", localName: "чрезвычайно"},
+	"E":      {name: "extended", localName: "вытянутая"},
+	"f":      {name: "following", localName: "к востоку"},
+	"F":      {name: "faint", localName: "слабая"},
+	"g":      {name: "gradually", localName: "постепенно"},
+	"gr":     {name: "group", localName: "группа"},
+	"i":      {name: "irregular", localName: "неправильная"},
+	"iF":     {name: "irregular figure", localName: "неправильной формы"},
+	"inv":    {name: "involved", localName: "погружённая"},
+	"l":      {name: "little", localName: "немного"},
+	"L":      {name: "large", localName: "большая"},
+	"m":      {name: "much", localName: "намного"},
+	"mm":     {name: "mixed magnitudes", localName: "звёзды разной величины"},
+	"M":      {name: "in the middle", localName: "в середине"},
+	"n":      {name: "north", localName: "север"},
+	"neb":    {name: "nebula", localName: "туманность"},
+	"nebs":   {name: "nebulous", localName: "туманная"},
+	"neby":   {name: "nebulosity", localName: "туманность"},
+	"nf":     {name: "north following", localName: "к северо-востоку"},
+	"np":     {name: "north preceding", localName: "к северо-западу"},
+	"N":      {name: "nucleus", localName: "ядро"},
+	"p":      {name: "pretty", localName: "довольно"},
+	"pf":     {name: "preceding-following", localName: "с запада на восток"},
+	"pg":     {name: "pretty gradually", localName: "довольно постепенно"},
+	"pm":     {name: "pretty much", localName: "довольно сильно"},
+	"ps":     {name: "pretty suddenly", localName: "довольно резко"},
+	"plan":   {name: "planetary nebula", localName: "планетарная туманность"},
+	"prob":   {name: "probably", localName: "вероятно"},
+	"P":      {name: "poor", localName: "бедное"},
+	"r":      {name: "resolvable", localName: "разрешимая"},
+	"rr":     {name: "partially resolved", localName: "частично разрешённая"},
+	"rrr":    {name: "well resolved", localName: "хорошо разрешённая"},
+	"R":      {name: "round", localName: "круглая"},
+	"RR":     {name: "exactly round", localName: "совершенно круглая"},
+	"Ri":     {name: "rich", localName: "богатое"},
+	"s":      {name: "suddenly", localName: "резко"},
+	"sc":     {name: "scattered", localName: "рассеянные"},
+	"sev":    {name: "several", localName: "несколько"},
+	"sf":     {name: "south following", localName: "к юго-востоку"},
+	"sp":     {name: "south preceding", localName: "к юго-западу"},
+	"st":     {name: "stars", localName: "звёзды"},
+	"stell":  {name: "stellar", localName: "звездообразная"},
+	"susp":   {name: "suspected", localName: "предполагаемая"},
+	"S":      {name: "small", localName: "маленькая"},
+	"v":      {name: "very", localName: "очень"},
+	"vv":     {name: "very very", localName: "очень-очень"},
+	"var":    {name: "variable", localName: "переменная"},
+}
+
+// dreyerWords перевод английских слов, которые встречаются в описаниях без сокращения.
+var dreyerWords = map[string]string{
+	"of":   "из",
+	"in":   "в",
+	"and":  "и",
+	"to":   "к",
+	"with": "с",
+	"near": "рядом с",
+}
+
+// Сокращения из одной буквы, значение которых зависит от положения в слове.
+var (
+	dreyerPreceding = dreyerTerm{name: "preceding", localName: "к западу"}
+	dreyerSouth     = dreyerTerm{name: "south", localName: "юг"}
+	dreyerLong      = dreyerTerm{name: "long", localName: "длинная"}
+)
+
+// dreyerStars расшифровка обозначений звёзд: *, ** и ***.
+var dreyerStars = [...]dreyerTerm{
+	{name: "star", localName: "звезда"},
+	{name: "double star", localName: "двойная звезда"},
+	{name: "triple star", localName: "тройная звезда"},
+}
+
+// dreyerRemarkable расшифровка обозначений примечательности: !, !! и !!!.
+var dreyerRemarkable = [...]dreyerTerm{
+	{name: "remarkable", localName: "примечательный объект"},
+	{name: "very remarkable", localName: "очень примечательный объект"},
+	{name: "magnificent", localName: "великолепный объект"},
+}
+
+// DecodeNGCDescription расшифровывает описание объекта NGC, записанное сокращениями Дрейера.
+// Нераспознанные сокращения, числа и текст в скобках сохраняются как есть.
+func DecodeNGCDescription(description string) NGCDescription {
+	result := NGCDescription{Code: description}
+	var names, localNames []string
+	for _, phrase := range splitDreyerPhrases(description) {
+		var phraseNames, phraseLocalNames, tokens []string
+		for _, word := range splitDreyerWords(phrase) {
+			// обычные слова проверяются до разбиения на сокращения: "in" — это не "i" + "n"
+			if localName, ok := dreyerWords[word]; ok {
+				phraseNames = append(phraseNames, word)
+				phraseLocalNames = append(phraseLocalNames, localName)
+				continue
+			}
+			terms, codes := getDreyerTerms(word)
+			if terms == nil {
+				phraseNames = append(phraseNames, word)
+				phraseLocalNames = append(phraseLocalNames, word)
+				continue
+			}
+			for i, term := range terms {
+				if term.name == "" {
+					// numbers, degrees and other text without abbreviations
+					phraseNames = append(phraseNames, codes[i])
+					phraseLocalNames = append(phraseLocalNames, codes[i])
+					continue
+				}
+				if strings.HasPrefix(codes[i], "!") && len(codes[i]) > result.Remarkable {
+					result.Remarkable = len(codes[i])
+				}
+				tokens = append(tokens, codes[i])
+				if last := len(phraseNames) - 1; last >= 0 && dreyerWords[phraseNames[last]] != "" &&
+					strings.HasPrefix(term.name, phraseNames[last]+" ") {
+					// "in M" — in the middle, а не in in the middle
+					phraseNames, phraseLocalNames = phraseNames[:last], phraseLocalNames[:last]
+				}
+				phraseNames = append(phraseNames, term.name)
+				phraseLocalNames = append(phraseLocalNames, term.localName)
+			}
+		}
+		names = append(names, strings.Join(phraseNames, " "))
+		localNames = append(localNames, strings.Join(phraseLocalNames, " "))
+		result.classify(phrase, tokens)
+	}
+	result.Name = strings.Join(names, ", ")
+	result.LocalName = strings.Join(localNames, ", ")
+	return result
+}
+
+// classify относит фразу описания к яркости, размеру, форме или концентрации по последнему сокращению.
+func (d *NGCDescription) classify(phrase string, tokens []string) {
+	if len(tokens) == 0 {
+		return
+	}
+	for _, token := range tokens {
+		if token == "M" || token == "N" || token == "stell" || token == "biN" {
+			if d.Condensation == "" {
+				d.Condensation = phrase
+			}
+			return
+		}
+	}
+	var field *string
+	switch tokens[len(tokens)-1] {
+	case "F", "B":
+		field = &d.Brightness
+	case "S", "L":
+		field = &d.Size
+	case "R", "RR", "E", "iF", "annul":
+		field = &d.Shape
+	default:
+		return
+	}
+	if *field == "" {
+		*field = phrase
+	}
+}
+
+// splitDreyerPhrases разбивает описание на фразы по запятым вне скобок.
+func splitDreyerPhrases(description string) []string {
+	var phrases []string
+	depth, start := 0, 0
+	for i, r := range description + "," {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == ',' && depth == 0:
+			if phrase := strings.TrimSpace(description[start:i]); phrase != "" {
+				phrases = append(phrases, phrase)
+			}
+			start = i + 1
+		}
+	}
+	return phrases
+}
+
+// splitDreyerWords разбивает фразу на слова по пробелам; текст в скобках остаётся одним словом.
+func splitDreyerWords(phrase string) []string {
+	var words []string
+	for phrase != "" {
+		phrase = strings.TrimLeft(phrase, " ")
+		if phrase == "" {
+			break
+		}
+		end := strings.IndexByte(phrase, ' ')
+		if phrase[0] == '(' {
+			end = strings.IndexByte(phrase, ')') + 1
+		}
+		if end <= 0 {
+			end = len(phrase)
+		}
+		words = append(words, phrase[:end])
+		phrase = phrase[end:]
+	}
+	return words
+}
+
+// getDreyerTerms возвращает расшифровку слова, если оно целиком является сокращением, иначе разбивает слово
+// на сокращения, выбирая самое длинное известное сокращение. Если слово целиком не раскладывается на сокращения (обычные слова "of", "in", названия), возвращается nil.
+// Для чисел и прочего текста внутри слова возвращается пустая расшифровка.
+func getDreyerTerms(word string) ([]dreyerTerm, []string) {
+	if strings.HasPrefix(word, "(") {
+		return nil, nil
+	}
+	if _, ok := dreyerTerms[word]; ok {
+		term, _ := getDreyerTerm(word, true, true)
+		return []dreyerTerm{term}, []string{word}
+	}
+	var codes []string
+	for rest := word; rest != ""; {
+		length := getDreyerTokenLength(rest)
+		codes = append(codes, rest[:length])
+		rest = rest[length:]
+	}
+	terms := make([]dreyerTerm, len(codes))
+	for i, code := range codes {
+		if !isDreyerLetter(code[0]) && code[0] != '*' && code[0] != '!' {
+			continue
+		}
+		term, ok := getDreyerTerm(code, i == len(codes)-1, len(codes) == 1)
+		if !ok {
+			return nil, nil
+		}
+		terms[i] = term
+	}
+	return terms, codes
+}
+
+func isDreyerLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func getDreyerTokenLength(word string) int {
+	switch c := word[0]; {
+	case c == '*' || c == '!':
+		// *, **, *** and !, !!, !!!, optionally followed by magnitude: *10
+		length := 1
+		for length < len(word) && length < 3 && word[length] == c {
+			length++
+		}
+		if c == '*' {
+			for length < len(word) && (word[length] >= '0' && word[length] <= '9' || word[length] == '.') {
+				length++
+			}
+		}
+		return length
+	case isDreyerLetter(c):
+		for length := len(word); length > 1; length-- {
+			if _, ok := dreyerTerms[word[:length]]; ok {
+				return length
+			}
+		}
+		return 1
+	}
+	// numbers, parentheses and other text are kept until the next letter;
+	// units after a number (90deg, 4m) are kept with the number
+	length := 1
+	for length < len(word) && !isDreyerLetter(word[length]) {
+		length++
+	}
+	if c := word[length-1]; c >= '0' && c <= '9' {
+		for length < len(word) && isDreyerLetter(word[length]) {
+			length++
+		}
+	}
+	return length
+}
+
+func getDreyerTerm(code string, last, alone bool) (dreyerTerm, bool) {
+	switch code[0] {
+	case '*':
+		stars := strings.TrimRight(code, "0123456789.")
+		term := dreyerStars[len(stars)-1]
+		if magnitude := code[len(stars):]; magnitude != "" {
+			term.name += " of magnitude " + magnitude
+			term.localName += " величины " + magnitude
+		}
+		return term, true
+	case '!':
+		return dreyerRemarkable[len(code)-1], true
+	}
+	// a single letter without modified word means direction or length, not a modifier
+	switch {
+	case code == "p" && last:
+		return dreyerPreceding, true
+	case code == "s" && alone:
+		return dreyerSouth, true
+	case code == "l" && alone:
+		return dreyerLong, true
+	}
+	term, ok := dreyerTerms[code]
+	return term, ok
+}
@@ -0,0 +1,179 @@
+package gorewind
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecodeNGCDescription(t *testing.T) {
+	for _, test := range []struct {
+		object      string
+		description string
+		name        string
+		localName   string
+		remarkable  int
+	}{
+		{
+			object:      "NGC 224",
+			description: "!!! eB, eL, vmE (Andromeda)",
+			name:        "magnificent extremely bright, extremely large, very much extended (Andromeda)",
+			localName:   "великолепный объект чрезвычайно яркая, чрезвычайно большая, очень намного вытянутая (Andromeda)",
+			remarkable:  3,
+		},
+		{
+			object:      "NGC 1976",
+			description: "!!! Theta Orionis and the great neb",
+			name:        "magnificent Theta Orionis and the great nebula",
+			localName:   "великолепный объект Theta Orionis и the great туманность",
+			remarkable:  3,
+		},
+		{
+			object:      "star in the middle",
+			description: "*10 in M",
+			name:        "star of magnitude 10 in the middle",
+			localName:   "звезда величины 10 в середине",
+		},
+		{
+			object:      "galaxy",
+			description: "vF, S, R, gbM",
+			name:        "very faint, small, round, gradually brighter in the middle",
+			localName:   "очень слабая, маленькая, круглая, постепенно ярче в середине",
+		},
+		{
+			object:      "position angle",
+			description: "lE 90deg",
+			name:        "little extended 90deg",
+			localName:   "немного вытянутая 90deg",
+		},
+		{
+			object:      "open cluster",
+			description: "Cl, pRi, lC, st 9...13",
+			name:        "cluster, pretty rich, little compressed, stars 9...13",
+			localName:   "скопление, довольно богатое, немного сжатая, звёзды 9...13",
+		},
+	} {
+		d := DecodeNGCDescription(test.description)
+		if d.Name != test.name {
+			t.Errorf("%s %q: name %q, want %q", test.object, test.description, d.Name, test.name)
+		}
+		if d.LocalName != test.localName {
+			t.Errorf("%s %q: local name %q, want %q", test.object, test.description, d.LocalName, test.localName)
+		}
+		if d.Remarkable != test.remarkable {
+			t.Errorf("%s %q: remarkable %d, want %d", test.object, test.description, d.Remarkable, test.remarkable)
+		}
+	}
+}
+
+func TestDecodeNGCDescriptionClassify(t *testing.T) {
+	d := DecodeNGCDescription("vF, S, R, gbM")
+	if d.Brightness != "vF" || d.Size != "S" || d.Shape != "R" || d.Condensation != "gbM" {
+		t.Errorf("classified as %q, %q, %q, %q", d.Brightness, d.Size, d.Shape, d.Condensation)
+	}
+}
+
+// Строки каталога VII/118 (ngc2000.dat): галактика, шаровое скопление со склонением -00°49′ и туманность IC.
+const testNGCLines = "  224 Gx  00 42.7  +41 16 s  And  185.   3.4  !!! eB, eL, vmE (Andromeda)\n" +
+	" 7089 Gb  21 33.5  -00 49 s  Aqr  12.9   6.5  !! vB, vL, eC, iR, st vF\n" +
+	"I 434 Nb  05 41.0  -02 24 s  Ori   60.        F, vL, dif\n"
+
+func TestNGCReaderRecord(t *testing.T) {
+	names := fmt.Sprintf("%-36s%5s\n", "Andromeda Galaxy", "224") // названия в байтах 1-35, номер в 37-41
+	reader := NewNGCReader(strings.NewReader(testNGCLines), strings.NewReader(names))
+	tests := []struct {
+		catalogue   string
+		index       uint
+		objectType  NGCObjectType
+		ra, dec     float64 // градусы
+		size        float64
+		magnitude   float64
+		description string
+		names       []string
+	}{
+		{"NGC", 224, NGCGalaxy, 10.675, 41 + 16.0/60, 185, 3.4, "!!! eB, eL, vmE (Andromeda)", []string{"Andromeda Galaxy"}},
+		{"NGC", 7089, NGCGlobularCluster, 323.375, -49.0 / 60, 12.9, 6.5, "!! vB, vL, eC, iR, st vF", nil},
+		{"IC", 434, NGCNebula, 85.25, -(2 + 24.0/60), 60, math.NaN(), "F, vL, dif", nil},
+	}
+	for _, test := range tests {
+		record, err := reader.ReadRecord()
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := test.catalogue + " " + strconv.Itoa(int(test.index))
+		if record.Catalogue != test.catalogue || record.Index != test.index || record.Type != test.objectType {
+			t.Errorf("%s: %s %d type %s", name, record.Catalogue, record.Index, record.Type)
+		}
+		if math.Abs(record.Coords.Longitude.Degrees()-test.ra) > 1e-9 || math.Abs(record.Coords.Latitude.Degrees()-test.dec) > 1e-9 {
+			t.Errorf("%s: coords %v°, %v°, want %v°, %v°", name, record.Coords.Longitude.Degrees(), record.Coords.Latitude.Degrees(), test.ra, test.dec)
+		}
+		if record.Size != test.size || !(record.Magnitude == test.magnitude || math.IsNaN(test.magnitude) && math.IsNaN(record.Magnitude)) {
+			t.Errorf("%s: size %v, magnitude %v, want %v, %v", name, record.Size, record.Magnitude, test.size, test.magnitude)
+		}
+		if record.Description != test.description || record.Constellation == "" {
+			t.Errorf("%s: description %q, constellation %q", name, record.Description, record.Constellation)
+		}
+		if len(record.Names) != len(test.names) || len(test.names) > 0 && record.Names[0] != test.names[0] {
+			t.Errorf("%s: names %q, want %q", name, record.Names, test.names)
+		}
+	}
+	if _, err := reader.ReadRecord(); err != io.EOF {
+		t.Errorf("after last record: %v, want io.EOF", err)
+	}
+}
+
+func TestNGCReaderParseError(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		field string
+		err   error
+	}{
+		{"unknown type", "  224 Xx  00 42.7  +41 16 s  And  185.   3.4  !!! eB", "Type", nil},
+		{"bad magnitude", "  224 Gx  00 42.7  +41 16 s  And  185.   3.x  !!! eB", "mag", strconv.ErrSyntax},
+		{"truncated line", "  224 Gx  00 4", "DEd", ErrEmptyField},
+		{"number only", "  224", "RAh", ErrEmptyField},
+	}
+	first := strings.SplitAfter(testNGCLines, "\n")[0]
+	for _, test := range tests {
+		reader := NewNGCReader(strings.NewReader(first+test.line+"\n"), nil)
+		if _, err := reader.ReadRecord(); err != nil {
+			t.Fatalf("%s: first record: %v", test.name, err)
+		}
+		_, err := reader.ReadRecord()
+		var parseError *ParseError
+		if !errors.As(err, &parseError) {
+			t.Errorf("%s: error %v, want *ParseError", test.name, err)
+			continue
+		}
+		if parseError.Catalogue != CatalogueNGC || parseError.Line != 2 || parseError.Field != test.field || test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%s: %s line %d field %s: %v, want line 2 field %s",
+				test.name, parseError.Catalogue, parseError.Line, parseError.Field, parseError.Err, test.field)
+		}
+	}
+}
+
+func TestNGCReaderLenient(t *testing.T) {
+	lines := strings.SplitAfter(testNGCLines, "\n")
+	data := lines[0] + "  224 Xx  00 42.7  +41 16 s  And  185.   3.4\n" + lines[1] + "  224 Gx  00 4\n" + lines[2]
+	reader := NewNGCReader(strings.NewReader(data), nil)
+	reader.Lenient = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("%d records, want 3", len(records))
+	}
+	stats := reader.Stats()
+	if stats.Read != 3 || stats.Failed != 2 || len(stats.Errors) != 2 {
+		t.Fatalf("stats %+v, want 3 read and 2 failed", stats)
+	}
+	if stats.Errors[0].Line != 2 || stats.Errors[0].Field != "Type" || stats.Errors[1].Line != 4 || stats.Errors[1].Field != "DEd" {
+		t.Errorf("errors %v, want Type at line 2 and DEd at line 4", stats.Errors)
+	}
+}
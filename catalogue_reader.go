@@ -0,0 +1,116 @@
+package gorewind
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// ErrStop возвращается функцией обхода каталога для досрочной остановки чтения.
+// Сам метод Visit в этом случае возвращает nil.
+var ErrStop = errors.New("stop reading catalogue")
+
+// maxLineLength максимальная длина строки каталога.
+// Строки GeoNames с альтернативными названиями бывают длиннее стандартного буфера bufio.Scanner.
+const maxLineLength = 1 << 20
+
+// DefaultMaxErrors количество ошибок разбора, сохраняемых в ReadStats в нестрогом режиме по умолчанию.
+const DefaultMaxErrors = 100
+
+// ReadOptions параметры чтения каталога. Для чтения из файла их принимают функции Read*WithOptions,
+// например, ReadBSCCatalogueWithOptions, для чтения из io.Reader — поле ReadOptions типов *Reader.
+type ReadOptions struct {
+	// Member имя файла внутри zip-архива с несколькими файлами для функций Read*WithOptions.
+	// Типы *Reader читают данные как есть: сжатый поток нужно предварительно распаковать функцией Decompress.
+	Member string
+	// Lenient включает нестрогий режим: записи с ошибками разбора пропускаются, а не прерывают чтение.
+	Lenient bool
+	// MaxErrors ограничивает количество ошибок, сохраняемых в ReadStats.Errors; 0 означает DefaultMaxErrors.
+	MaxErrors int
+}
+
+// ReadStats статистика чтения каталога.
+type ReadStats struct {
+	Read    int           // количество прочитанных записей
+	Skipped int           // количество пропущенных записей без данных, например, записей BSC без координат
+	Failed  int           // количество записей с ошибками разбора, пропущенных в нестрогом режиме
+	Errors  []*ParseError // ошибки разбора, не более ReadOptions.MaxErrors
+}
+
+// readerState параметры и статистика чтения каталога.
+type readerState struct {
+	ReadOptions
+	stats ReadStats
+}
+
+// Stats возвращает статистику чтения каталога.
+func (s *readerState) Stats() ReadStats {
+	return s.stats
+}
+
+// handleError учитывает ошибку разбора записи и возвращает nil, если в нестрогом режиме запись можно пропустить.
+func (s *readerState) handleError(err error) error {
+	var parseError *ParseError
+	if !s.Lenient || !errors.As(err, &parseError) {
+		return err
+	}
+	s.stats.Failed++
+	maxErrors := s.MaxErrors
+	if maxErrors == 0 {
+		maxErrors = DefaultMaxErrors
+	}
+	if len(s.stats.Errors) < maxErrors {
+		s.stats.Errors = append(s.stats.Errors, parseError)
+	}
+	return nil
+}
+
+// lineReader построчное чтение каталога.
+type lineReader struct {
+	readerState
+
+	source  io.Reader
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *lineReader) readLine() (string, error) {
+	if r.scanner == nil {
+		r.scanner = bufio.NewScanner(r.source)
+		r.scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	}
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	r.line++
+	return r.scanner.Text(), nil
+}
+
+// Line возвращает номер последней прочитанной строки (с 1).
+func (r *lineReader) Line() int {
+	return r.line
+}
+
+// visit вызывает next до конца каталога, ошибки или отмены контекста.
+func visit(ctx context.Context, next func() error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := next(); err != nil {
+			if err == io.EOF || errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+}
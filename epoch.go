@@ -0,0 +1,65 @@
+package gorewind
+
+import "time"
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// Epoch момент времени — юлианская дата в шкале земного времени TT.
+type Epoch float64
+
+const (
+	J2000 Epoch = 2451545.0    // 2000 January 1.5 TT, равноденствие BSC, NGC 2000.0 и каталогов FK5 и ICRS
+	B1950 Epoch = 2433282.4235 // бесселева эпоха 1950.0, равноденствие каталога FK4
+
+	// JulianCentury юлианское столетие в сутках.
+	JulianCentury = 36525
+	// unixEpoch юлианская дата 1970-01-01 00:00.
+	unixEpoch = 2440587.5
+)
+
+// NewJulianEpoch создаёт эпоху, заданную юлианским годом, например, 2000.0 для J2000.
+func NewJulianEpoch(year float64) Epoch {
+	return J2000 + Epoch((year-2000)*365.25)
+}
+
+// NewBesselianEpoch создаёт эпоху, заданную бесселевым годом, например, 1950.0 для B1950.
+func NewBesselianEpoch(year float64) Epoch {
+	return Epoch(2415020.31352 + (year-1900)*365.242198781)
+}
+
+// NewEpoch создаёт эпоху для момента времени t, заданного в шкале UTC (до 1960 года — UT1).
+func NewEpoch(t time.Time) Epoch {
+	return Epoch(getJulianDate(t) + getTTMinusUTC(t)/secondsPerDay)
+}
+
+// JulianDate возвращает юлианскую дату.
+func (e Epoch) JulianDate() float64 {
+	return float64(e)
+}
+
+// JulianYear возвращает юлианский год, например, 2000.0 для J2000.
+func (e Epoch) JulianYear() float64 {
+	return 2000 + float64(e-J2000)/365.25
+}
+
+// BesselianYear возвращает бесселев год, например, 1950.0 для B1950.
+func (e Epoch) BesselianYear() float64 {
+	return 1900 + (float64(e)-2415020.31352)/365.242198781
+}
+
+// GetCenturies возвращает количество юлианских столетий от J2000.
+func (e Epoch) GetCenturies() float64 {
+	return float64(e-J2000) / JulianCentury
+}
+
+// Time возвращает момент времени UTC (до 1960 года — UT1), соответствующий эпохе.
+func (e Epoch) Time() time.Time {
+	// разница TT − UTC берётся на момент, отличающийся от искомого не больше чем на минуту,
+	// а второе приближение уточняет её около високосных секунд
+	t := getTime(float64(e))
+	t = getTime(float64(e) - getTTMinusUTC(t)/secondsPerDay)
+	return getTime(float64(e) - getTTMinusUTC(t)/secondsPerDay)
+}
@@ -0,0 +1,318 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Системы координат образуют дерево с корнем ICRS: у каждой системы есть родительская, к которой и от которой
+// она преобразуется напрямую. Преобразование между любыми двумя системами поднимается от исходной системы
+// до общего предка и спускается к целевой. Географические координаты образуют отдельное дерево:
+// преобразование между ними и небесными координатами не определено.
+//
+//	ICRS ─┬─ FK5 (равноденствие) ─┬─ FK4 B1950 (только от FK5 J2000)
+//	      │                       └─ Ecliptic (средняя эклиптика и равноденствие)
+//	      ├─ Apparent (видимое место) ── Horizontal (наблюдатель и момент)
+//	      └─ Galactic ── Supergalactic
+//	Geographic
+
+// CoordinateSystem система координат.
+type CoordinateSystem int
+
+const (
+	ICRS          CoordinateSystem = iota // International Celestial Reference System; не отличается от FK5 J2000
+	FK5                                   // средний экватор и равноденствие эпохи Frame.Epoch, каталоги BSC и NGC
+	FK4                                   // средний экватор и равноденствие B1950 каталога FK4
+	Ecliptic                              // средняя эклиптика и равноденствие эпохи Frame.Epoch
+	Apparent                              // истинный экватор и равноденствие эпохи Frame.Epoch, видимое место
+	Galactic                              // галактические координаты
+	Supergalactic                         // сверхгалактические координаты
+	Horizontal                            // азимут от севера через восток и высота для Frame.Observer в момент Frame.Epoch
+	Geographic                            // географические долгота и широта WGS 84, каталоги GeoNames
+)
+
+var coordinateSystemNames = [...]string{
+	ICRS:          "ICRS",
+	FK5:           "FK5",
+	FK4:           "FK4",
+	Ecliptic:      "Ecliptic",
+	Apparent:      "Apparent",
+	Galactic:      "Galactic",
+	Supergalactic: "Supergalactic",
+	Horizontal:    "Horizontal",
+	Geographic:    "Geographic",
+}
+
+// String возвращает название системы координат.
+func (s CoordinateSystem) String() string {
+	if s < 0 || int(s) >= len(coordinateSystemNames) {
+		return "CoordinateSystem(" + strconv.Itoa(int(s)) + ")"
+	}
+	return coordinateSystemNames[s]
+}
+
+// ErrFrameConversion ошибка преобразования между системами координат, для которых оно не определено.
+var ErrFrameConversion = errors.New("undefined frame conversion")
+
+// Frame система отсчёта: система координат, эпоха и наблюдатель, если они нужны системе.
+// Системы отсчёта сравнимы оператором ==; создавать их нужно функциями New*Frame или брать готовые Frame*.
+type Frame struct {
+	System   CoordinateSystem
+	Epoch    Epoch     // равноденствие для FK5, FK4, Ecliptic и Apparent, момент наблюдения для Horizontal
+	Observer *Location // наблюдатель для Horizontal
+}
+
+var (
+	FrameICRS          = Frame{System: ICRS}
+	FrameFK5J2000      = Frame{System: FK5, Epoch: J2000}
+	FrameFK4B1950      = Frame{System: FK4, Epoch: B1950}
+	FrameGalactic      = Frame{System: Galactic}
+	FrameSupergalactic = Frame{System: Supergalactic}
+	FrameGeographic    = Frame{System: Geographic}
+)
+
+// NewFK5Frame создаёт систему отсчёта FK5 со средним экватором и равноденствием эпохи equinox.
+func NewFK5Frame(equinox Epoch) Frame {
+	return Frame{System: FK5, Epoch: equinox}
+}
+
+// NewEclipticFrame создаёт эклиптическую систему отсчёта со средней эклиптикой и равноденствием эпохи equinox.
+func NewEclipticFrame(equinox Epoch) Frame {
+	return Frame{System: Ecliptic, Epoch: equinox}
+}
+
+// NewApparentFrame создаёт систему отсчёта видимых мест на эпоху epoch.
+func NewApparentFrame(epoch Epoch) Frame {
+	return Frame{System: Apparent, Epoch: epoch}
+}
+
+// NewHorizontalFrame создаёт горизонтальную систему отсчёта для наблюдателя observer в момент t.
+func NewHorizontalFrame(observer *Location, t time.Time) Frame {
+	return Frame{System: Horizontal, Epoch: NewEpoch(t), Observer: observer}
+}
+
+// String возвращает название системы отсчёта с эпохой, например, «FK5 J2000.000».
+func (f Frame) String() string {
+	switch f.System {
+	case FK5, Ecliptic, Apparent:
+		return f.System.String() + " J" + strconv.FormatFloat(f.Epoch.JulianYear(), 'f', 3, 64)
+	case FK4:
+		return f.System.String() + " B" + strconv.FormatFloat(f.Epoch.BesselianYear(), 'f', 3, 64)
+	case Horizontal:
+		name := f.System.String() + " " + f.Epoch.Time().Format(time.RFC3339)
+		if f.Observer != nil && f.Observer.Name != "" {
+			name += " " + f.Observer.Name
+		}
+		return name
+	default:
+		return f.System.String()
+	}
+}
+
+// FramedCoords сферические координаты с системой отсчёта, к которой они отнесены.
+type FramedCoords struct {
+	SphericalCoords
+	Frame Frame
+}
+
+// In возвращает координаты, отнесённые к системе отсчёта frame.
+func (c SphericalCoords) In(frame Frame) FramedCoords {
+	return FramedCoords{SphericalCoords: c, Frame: frame}
+}
+
+// GetFramedCoords возвращает координаты объекта, отнесённые к FK5 J2000 — равноденствию каталогов BSC и NGC.
+func (ao *AstronomicalObject) GetFramedCoords() FramedCoords {
+	return ao.Coords.In(FrameFK5J2000)
+}
+
+// GetFramedCoords возвращает географические координаты места.
+func (l *Location) GetFramedCoords() FramedCoords {
+	return l.Coords.In(FrameGeographic)
+}
+
+// Transform возвращает координаты, отнесённые к системе отсчёта to.
+// Если преобразование не определено, возвращается ошибка ErrFrameConversion.
+func (c FramedCoords) Transform(to Frame) (FramedCoords, error) {
+	if c.Frame == to {
+		return c, nil
+	}
+	from, err := c.Frame.getAncestors()
+	if err != nil {
+		return c, err
+	}
+	target, err := to.getAncestors()
+	if err != nil {
+		return c, err
+	}
+
+	// общий предок ближе всего к исходной системе
+	common, down := -1, -1
+	for i := range from {
+		for j := range target {
+			if from[i] == target[j] {
+				common, down = i, j
+				break
+			}
+		}
+		if common >= 0 {
+			break
+		}
+	}
+	if common < 0 {
+		return c, fmt.Errorf("%w: %s to %s", ErrFrameConversion, c.Frame, to)
+	}
+
+	coords := c.SphericalCoords
+	for i := 0; i < common; i++ {
+		coords = from[i].toParent(coords)
+	}
+	for j := down - 1; j >= 0; j-- {
+		coords = target[j].fromParent(coords)
+	}
+	return coords.In(to), nil
+}
+
+// getParent возвращает родительскую систему отсчёта; у корневых систем ICRS и Geographic родительской нет.
+func (f Frame) getParent() (Frame, bool, error) {
+	switch f.System {
+	case ICRS, Geographic:
+		return Frame{}, false, nil
+	case FK5, Apparent, Galactic:
+		return FrameICRS, true, nil
+	case FK4:
+		if f.Epoch != B1950 {
+			return Frame{}, false, fmt.Errorf("%w: %s, only B1950 is supported", ErrFrameConversion, f)
+		}
+		return FrameFK5J2000, true, nil
+	case Ecliptic:
+		return NewFK5Frame(f.Epoch), true, nil
+	case Supergalactic:
+		return FrameGalactic, true, nil
+	case Horizontal:
+		if f.Observer == nil {
+			return Frame{}, false, fmt.Errorf("%w: %s without observer", ErrFrameConversion, f)
+		}
+		return NewApparentFrame(f.Epoch), true, nil
+	default:
+		return Frame{}, false, fmt.Errorf("%w: unknown %s", ErrFrameConversion, f.System)
+	}
+}
+
+// getAncestors возвращает систему отсчёта и всех её предков до корня.
+func (f Frame) getAncestors() ([]Frame, error) {
+	ancestors := []Frame{f}
+	for {
+		parent, ok, err := ancestors[len(ancestors)-1].getParent()
+		if err != nil || !ok {
+			return ancestors, err
+		}
+		ancestors = append(ancestors, parent)
+	}
+}
+
+// toParent преобразует координаты к родительской системе отсчёта.
+func (f Frame) toParent(c SphericalCoords) SphericalCoords {
+	switch f.System {
+	case FK5:
+		return c.GetPrecessed(f.Epoch, J2000)
+	case FK4:
+		return getFK5FromFK4(c)
+	case Ecliptic:
+		return c.GetEquatorialFromEcliptic(f.Epoch)
+	case Apparent:
+		return getCatalogueFromApparent(c, f.Epoch)
+	case Galactic:
+		return c.GetEquatorialFromGalactic()
+	case Supergalactic:
+		return c.GetGalacticFromSupergalactic()
+	case Horizontal:
+		return getEquatorialFromHorizontal(c, f.getLocalSiderealTime(), f.Observer.Coords.Latitude.float64)
+	default:
+		return c
+	}
+}
+
+// fromParent преобразует координаты из родительской системы отсчёта.
+func (f Frame) fromParent(c SphericalCoords) SphericalCoords {
+	switch f.System {
+	case FK5:
+		return c.GetPrecessed(J2000, f.Epoch)
+	case FK4:
+		return getFK4FromFK5(c)
+	case Ecliptic:
+		return c.GetEcliptic(f.Epoch)
+	case Apparent:
+		return c.GetApparent(f.Epoch)
+	case Galactic:
+		return c.GetGalactic()
+	case Supergalactic:
+		return c.GetSupergalactic()
+	case Horizontal:
+		return getHorizontal(c, f.getLocalSiderealTime(), f.Observer.Coords.Latitude.float64)
+	default:
+		return c
+	}
+}
+
+// getLocalSiderealTime возвращает истинное местное звёздное время наблюдателя горизонтальной системы отсчёта.
+func (f Frame) getLocalSiderealTime() float64 {
+	return normalizeLongitude(getApparentSiderealTime(f.Epoch) + f.Observer.Coords.Longitude.float64)
+}
+
+// Преобразование FK4 B1950 ↔ FK5 J2000 для объектов без собственного движения в FK5, как в функции iauFk45z
+// библиотеки SOFA на эпоху B1950: удаление E-членов аберрации и поворот матрицей Standish (1982).
+
+// fk4ETerms E-члены эллиптической аберрации в радианах.
+var fk4ETerms = [3]float64{-1.62557e-6, -0.31919e-6, -0.13843e-6}
+
+// fk4ToFK5 матрица поворота из FK4 B1950 в FK5 J2000.
+var fk4ToFK5 = [3][3]float64{
+	{+0.9999256782, -0.0111820611, -0.0048579477},
+	{+0.0111820610, +0.9999374784, -0.0000271765},
+	{+0.0048579479, -0.0000271474, +0.9999881997},
+}
+
+// getFK5FromFK4 возвращает координаты FK5 J2000 для координат FK4 B1950.
+func getFK5FromFK4(c SphericalCoords) SphericalCoords {
+	point := getUnitVector(c)
+	w := getDotProduct(point, fk4ETerms)
+	var corrected [3]float64
+	for axis := range corrected {
+		corrected[axis] = point[axis] - fk4ETerms[axis] + w*point[axis]
+	}
+	var result [3]float64
+	for row := range result {
+		result[row] = getDotProduct(fk4ToFK5[row], corrected)
+	}
+	return getVectorCoords(result, c.Radius)
+}
+
+// getFK4FromFK5 возвращает координаты FK4 B1950 для координат FK5 J2000; обратное преобразование к getFK5FromFK4.
+func getFK4FromFK5(c SphericalCoords) SphericalCoords {
+	point := getUnitVector(c)
+	// матрица почти ортогональна: обратная к ней совпадает с транспонированной до 1e-10
+	var rotated [3]float64
+	for column := range rotated {
+		for row := range point {
+			rotated[column] += fk4ToFK5[row][column] * point[row]
+		}
+	}
+	length := math.Sqrt(getDotProduct(rotated, rotated))
+	for axis := range rotated {
+		rotated[axis] /= length
+	}
+	w := getDotProduct(rotated, fk4ETerms)
+	var result [3]float64
+	for axis := range result {
+		result[axis] = rotated[axis] + fk4ETerms[axis] - w*rotated[axis]
+	}
+	return getVectorCoords(result, c.Radius)
+}
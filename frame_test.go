@@ -0,0 +1,104 @@
+package gorewind
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTransformFK4ToFK5(t *testing.T) {
+	// M31: B1950 00h40m00.1s +40°59′43″ → J2000 00h42m44.34s +41°16′08.6″
+	fk4 := NewClockCoords(0, 40, 0.1, 40, 59, 43).In(FrameFK4B1950)
+	want := NewClockCoords(0, 42, 44.34, 41, 16, 8.6)
+	fk5, err := fk4.Transform(FrameFK5J2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance := fk5.GetDistance(want); distance > 0.5*ArcSecond {
+		t.Errorf("FK5 %v, want %v (%.2f″)", fk5.SphericalCoords, want, distance/ArcSecond)
+	}
+	if fk5.Frame != FrameFK5J2000 {
+		t.Errorf("frame %s, want %s", fk5.Frame, FrameFK5J2000)
+	}
+}
+
+func TestTransformMatchesDirectConversions(t *testing.T) {
+	c := NewCoordsFromDegrees(187.7059, 12.3911)
+	equinox := NewJulianEpoch(2025)
+	tests := []struct {
+		to   Frame
+		want SphericalCoords
+	}{
+		{FrameICRS, c},
+		{FrameGalactic, c.GetGalactic()},
+		{FrameSupergalactic, c.GetGalactic().GetSupergalactic()},
+		{NewFK5Frame(equinox), c.GetPrecessed(J2000, equinox)},
+		{NewEclipticFrame(equinox), c.GetPrecessed(J2000, equinox).GetEcliptic(equinox)},
+		{NewApparentFrame(equinox), c.GetApparent(equinox)},
+	}
+	for _, test := range tests {
+		got, err := c.In(FrameFK5J2000).Transform(test.to)
+		if err != nil {
+			t.Errorf("%s: %v", test.to, err)
+			continue
+		}
+		if math.Abs(got.Longitude.Radians()-test.want.Longitude.Radians()) > 1e-12 ||
+			math.Abs(got.Latitude.Radians()-test.want.Latitude.Radians()) > 1e-12 {
+			t.Errorf("%s: %v, want %v", test.to, got.SphericalCoords, test.want)
+		}
+	}
+}
+
+func TestTransformRoundTrip(t *testing.T) {
+	observer := &Location{Name: "Moscow", Coords: NewCoordsFromDegrees(37.6173, 55.7558)}
+	moment := time.Date(2021, 1, 15, 21, 0, 0, 0, time.UTC)
+	chain := []Frame{
+		FrameFK4B1950,
+		FrameFK5J2000,
+		FrameICRS,
+		NewEclipticFrame(NewJulianEpoch(2025)),
+		NewHorizontalFrame(observer, moment),
+		FrameSupergalactic,
+		NewFK5Frame(NewJulianEpoch(1900)),
+		FrameFK4B1950,
+	}
+	start := NewClockCoords(6, 42, 56.7, -16, 38, 46).In(chain[0])
+	coords := start
+	for _, frame := range chain[1:] {
+		next, err := coords.Transform(frame)
+		if err != nil {
+			t.Fatalf("%s to %s: %v", coords.Frame, frame, err)
+		}
+		coords = next
+	}
+	if math.Abs(coords.Longitude.Radians()-start.Longitude.Radians()) > 1e-10 ||
+		math.Abs(coords.Latitude.Radians()-start.Latitude.Radians()) > 1e-10 {
+		t.Errorf("round trip %v, want %v", coords.SphericalCoords, start.SphericalCoords)
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	c := NewCoordsFromDegrees(10, 20)
+	tests := []struct {
+		name     string
+		from, to Frame
+	}{
+		{"horizontal without observer", FrameICRS, Frame{System: Horizontal, Epoch: J2000}},
+		{"from horizontal without observer", Frame{System: Horizontal, Epoch: J2000}, FrameICRS},
+		{"FK4 not at B1950", FrameICRS, Frame{System: FK4, Epoch: J2000}},
+		{"geographic to celestial", FrameGeographic, FrameICRS},
+		{"unknown system", FrameICRS, Frame{System: CoordinateSystem(100)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := c.In(test.from).Transform(test.to)
+			if !errors.Is(err, ErrFrameConversion) {
+				t.Fatalf("error %v, want ErrFrameConversion", err)
+			}
+			if result.Frame != test.from {
+				t.Errorf("frame %s after error, want %s", result.Frame, test.from)
+			}
+		})
+	}
+}
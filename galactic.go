@@ -0,0 +1,57 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import "math"
+
+// Галактическая система IAU 1958, отнесённая к J2000 (ICRS) как в каталоге Hipparcos: северный полюс Галактики
+// α = 192.85948°, δ = +27.12825°, галактическая долгота северного полюса мира 122.93192°.
+// Сверхгалактическая система G. de Vaucouleurs задана в галактических координатах: северный полюс l = 47.37°,
+// b = +6.32°, начало отсчёта долготы l = 137.37°, b = 0° совпадает с восходящим узлом сверхгалактического экватора.
+
+const (
+	galacticPoleLongitude      = 192.85948 * Degree
+	galacticPoleLatitude       = 27.12825 * Degree
+	galacticNodeLongitude      = (122.93192 - 90) * Degree // галактическая долгота восходящего узла экватора
+	supergalacticPoleLongitude = 47.37 * Degree
+	supergalacticPoleLatitude  = 6.32 * Degree
+	supergalacticNodeLongitude = 0.0 // начало отсчёта совпадает с узлом
+)
+
+// GetGalactic возвращает галактические координаты для экваториальных координат J2000.
+func (c SphericalCoords) GetGalactic() SphericalCoords {
+	return c.GetOffset(-galacticPoleLongitude-math.Pi/2, math.Pi/2-galacticPoleLatitude, galacticNodeLongitude)
+}
+
+// GetEquatorialFromGalactic возвращает экваториальные координаты J2000 для галактических координат;
+// обратное преобразование к GetGalactic.
+func (c SphericalCoords) GetEquatorialFromGalactic() SphericalCoords {
+	return c.GetOffset(-galacticNodeLongitude, galacticPoleLatitude-math.Pi/2, galacticPoleLongitude+math.Pi/2)
+}
+
+// GetSupergalactic возвращает сверхгалактические координаты для галактических координат.
+func (c SphericalCoords) GetSupergalactic() SphericalCoords {
+	return c.GetOffset(-supergalacticPoleLongitude-math.Pi/2, math.Pi/2-supergalacticPoleLatitude, supergalacticNodeLongitude)
+}
+
+// GetGalacticFromSupergalactic возвращает галактические координаты для сверхгалактических координат;
+// обратное преобразование к GetSupergalactic.
+func (c SphericalCoords) GetGalacticFromSupergalactic() SphericalCoords {
+	return c.GetOffset(-supergalacticNodeLongitude, supergalacticPoleLatitude-math.Pi/2, supergalacticPoleLongitude+math.Pi/2)
+}
+
+// FilterByGalacticLatitude возвращает объекты с экваториальными координатами J2000, галактическая широта которых
+// по модулю не меньше latitude (в радианах), в исходном порядке. Так из выборки исключается полоса Млечного Пути,
+// где галактики закрыты межзвёздным поглощением.
+func FilterByGalacticLatitude(items []Positioned, latitude float64) []Positioned {
+	var result []Positioned
+	for _, item := range items {
+		if hasPosition(item) && math.Abs(item.GetCoords().GetGalactic().Latitude.float64) >= latitude {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+// Определяющие точки галактической системы (Hipparcos, ICRS) и сверхгалактической системы de Vaucouleurs.
+func TestGalacticFrames(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform func(SphericalCoords) SphericalCoords
+		inverse   func(SphericalCoords) SphericalCoords
+		input     SphericalCoords
+		want      SphericalCoords
+		tolerance float64 // градусы
+	}{
+		{"galactic centre", SphericalCoords.GetGalactic, SphericalCoords.GetEquatorialFromGalactic,
+			NewCoordsFromDegrees(266.40499, -28.93617), NewCoordsFromDegrees(0, 0), 2e-5},
+		{"north celestial pole", SphericalCoords.GetGalactic, SphericalCoords.GetEquatorialFromGalactic,
+			NewCoordsFromDegrees(0, 90), NewCoordsFromDegrees(122.93192, 27.12825), 1e-6},
+		{"north galactic pole", SphericalCoords.GetGalactic, SphericalCoords.GetEquatorialFromGalactic,
+			NewCoordsFromDegrees(192.85948, 27.12825), NewCoordsFromDegrees(0, 90), 1e-6},
+		{"supergalactic origin", SphericalCoords.GetSupergalactic, SphericalCoords.GetGalacticFromSupergalactic,
+			NewCoordsFromDegrees(137.37, 0), NewCoordsFromDegrees(0, 0), 1e-6},
+		{"north supergalactic pole", SphericalCoords.GetSupergalactic, SphericalCoords.GetGalacticFromSupergalactic,
+			NewCoordsFromDegrees(47.37, 6.32), NewCoordsFromDegrees(0, 90), 1e-6},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.transform(test.input)
+			if distance := got.GetDistance(test.want); distance > test.tolerance*Degree {
+				t.Errorf("%.6f°, %.6f°, want %.6f°, %.6f°", got.Longitude.Degrees(), got.Latitude.Degrees(),
+					test.want.Longitude.Degrees(), test.want.Latitude.Degrees())
+			}
+			back := test.inverse(got)
+			if distance := back.GetDistance(test.input); distance > 1e-7 {
+				t.Errorf("round trip %.8f°, %.8f°", back.Longitude.Degrees(), back.Latitude.Degrees())
+			}
+		})
+	}
+}
+
+func TestGalacticRoundTrip(t *testing.T) {
+	c := NewCoordsFromDegrees(187.7059, 12.3911)
+	back := c.GetGalactic().GetSupergalactic().GetGalacticFromSupergalactic().GetEquatorialFromGalactic()
+	if math.Abs(back.Longitude.Radians()-c.Longitude.Radians()) > 1e-12 ||
+		math.Abs(back.Latitude.Radians()-c.Latitude.Radians()) > 1e-12 {
+		t.Errorf("round trip %v, want %v", back, c)
+	}
+}
+
+func TestFilterByGalacticLatitude(t *testing.T) {
+	centre := &AstronomicalObject{Name: "Galactic centre", Coords: NewCoordsFromDegrees(266.40499, -28.93617)}
+	pole := &AstronomicalObject{Name: "North galactic pole", Coords: NewCoordsFromDegrees(192.85948, 27.12825)}
+	result := FilterByGalacticLatitude([]Positioned{centre, pole}, 10*Degree)
+	if len(result) != 1 || result[0] != pole {
+		t.Errorf("result %v, want only the pole", result)
+	}
+}
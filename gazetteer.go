@@ -0,0 +1,189 @@
+package gorewind
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// Имена вспомогательных файлов GeoNames, используемые ReadGazetteer.
+const (
+	CountriesFileName = "countryInfo.txt"
+	Admin1FileName    = "admin1CodesASCII.txt"
+	Admin2FileName    = "admin2Codes.txt"
+	TimeZonesFileName = "timeZones.txt"
+)
+
+// Gazetteer связывает объекты GeoNames со странами, административными делениями и часовыми поясами.
+type Gazetteer struct {
+	Countries map[string]*Country       // страны по коду ISO
+	Admin1    map[string]*AdminDivision // административные деления первого уровня по коду вида RU.73
+	Admin2    map[string]*AdminDivision // административные деления второго уровня по коду вида RU.73.1234567
+	TimeZones map[string]*TimeZone      // часовые пояса по идентификатору IANA
+	// Names если задано, используется для названий стран и административных делений на других языках.
+	Names *NameResolver
+
+	mutex     sync.Mutex
+	locations map[string]*time.Location
+}
+
+// Place объект GeoNames вместе со страной, регионом, районом и часовым поясом.
+// Поля, которые не удалось определить, равны nil.
+type Place struct {
+	*Location
+	Country  *Country
+	Region   *AdminDivision // административное деление первого уровня
+	District *AdminDivision // административное деление второго уровня
+	Time     *time.Location // часовой пояс для расчёта местного времени
+}
+
+// NewGazetteer создаёт Gazetteer по прочитанным спискам; любой из них может быть пустым.
+func NewGazetteer(countries []*Country, admin1, admin2 []*AdminDivision, timeZones []*TimeZone) *Gazetteer {
+	g := Gazetteer{
+		Countries: make(map[string]*Country, len(countries)),
+		Admin1:    make(map[string]*AdminDivision, len(admin1)),
+		Admin2:    make(map[string]*AdminDivision, len(admin2)),
+		TimeZones: make(map[string]*TimeZone, len(timeZones)),
+		locations: make(map[string]*time.Location),
+	}
+	for _, country := range countries {
+		g.Countries[country.ISO] = country
+	}
+	for _, division := range admin1 {
+		g.Admin1[division.Code] = division
+	}
+	for _, division := range admin2 {
+		g.Admin2[division.Code] = division
+	}
+	for _, timeZone := range timeZones {
+		g.TimeZones[timeZone.ID] = timeZone
+	}
+	return &g
+}
+
+// ReadGazetteer читает countryInfo.txt, admin1CodesASCII.txt, admin2Codes.txt и timeZones.txt из каталога dir.
+// Отсутствующий admin2Codes.txt допускается: файл большой и нужен не всегда.
+func ReadGazetteer(dir string) (*Gazetteer, error) {
+	return ReadGazetteerFS(os.DirFS(dir))
+}
+
+// ReadGazetteerFS читает вспомогательные файлы GeoNames из файловой системы fsys аналогично ReadGazetteer.
+func ReadGazetteerFS(fsys fs.FS) (*Gazetteer, error) {
+	countries, err := ReadCountriesFS(fsys, CountriesFileName)
+	if err != nil {
+		return nil, err
+	}
+	admin1, err := ReadAdminDivisionsFS(fsys, Admin1FileName)
+	if err != nil {
+		return nil, err
+	}
+	admin2, err := ReadAdminDivisionsFS(fsys, Admin2FileName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	timeZones, err := ReadTimeZonesFS(fsys, TimeZonesFileName)
+	if err != nil {
+		return nil, err
+	}
+	return NewGazetteer(countries, admin1, admin2, timeZones), nil
+}
+
+// GetCountry возвращает страну location или nil.
+func (g *Gazetteer) GetCountry(location *Location) *Country {
+	return g.Countries[location.CountryCode]
+}
+
+// GetRegion возвращает административное деление первого уровня location или nil.
+func (g *Gazetteer) GetRegion(location *Location) *AdminDivision {
+	if location.Admin1Code == "" {
+		return nil
+	}
+	return g.Admin1[location.CountryCode+"."+location.Admin1Code]
+}
+
+// GetDistrict возвращает административное деление второго уровня location или nil.
+func (g *Gazetteer) GetDistrict(location *Location) *AdminDivision {
+	if location.Admin1Code == "" || location.Admin2Code == "" {
+		return nil
+	}
+	return g.Admin2[location.CountryCode+"."+location.Admin1Code+"."+location.Admin2Code]
+}
+
+// GetTimeLocation возвращает часовой пояс location.
+// Если база часовых поясов недоступна (например, без time/tzdata), используется постоянное смещение из timeZones.txt
+// без учёта летнего времени. Если часовой пояс неизвестен, возвращается nil.
+func (g *Gazetteer) GetTimeLocation(location *Location) *time.Location {
+	if location.TimeZone == "" {
+		return nil
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if timeLocation, ok := g.locations[location.TimeZone]; ok {
+		return timeLocation
+	}
+	timeLocation, err := time.LoadLocation(location.TimeZone)
+	if err != nil {
+		timeLocation = nil
+		if timeZone, ok := g.TimeZones[location.TimeZone]; ok {
+			offset := int(math.Round(timeZone.RawOffset * 3600))
+			timeLocation = time.FixedZone(timeZone.ID, offset)
+		}
+	}
+	if g.locations == nil {
+		g.locations = make(map[string]*time.Location)
+	}
+	g.locations[location.TimeZone] = timeLocation
+	return timeLocation
+}
+
+// GetPlace связывает location со страной, регионом, районом и часовым поясом.
+func (g *Gazetteer) GetPlace(location *Location) *Place {
+	return &Place{
+		Location: location,
+		Country:  g.GetCountry(location),
+		Region:   g.GetRegion(location),
+		District: g.GetDistrict(location),
+		Time:     g.GetTimeLocation(location),
+	}
+}
+
+// GetDisplayName возвращает полное название location на языке language, например, «Казань, Татарстан, Россия».
+// Названия, которых нет на языке language, выводятся на английском.
+// Регион не выводится, если он совпадает с названием объекта, как у Москвы.
+func (g *Gazetteer) GetDisplayName(location *Location, language string) string {
+	name := g.Names.GetLocalName(location, language)
+	if name == "" {
+		name = location.Name
+	}
+	parts := []string{name}
+	if region := g.GetRegion(location); region != nil {
+		regionName := g.getName(region.GeoNameID, language, region.Name)
+		if regionName != name {
+			parts = append(parts, regionName)
+		}
+	}
+	if country := g.GetCountry(location); country != nil {
+		parts = append(parts, g.getName(country.GeoNameID, language, country.Name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (g *Gazetteer) getName(geoNameID uint, language, defaultName string) string {
+	if g.Names == nil || geoNameID == 0 {
+		return defaultName
+	}
+	if name, ok := g.Names.GetName(geoNameID, language); ok {
+		return name
+	}
+	return defaultName
+}
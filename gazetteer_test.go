@@ -0,0 +1,91 @@
+package gorewind
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// testGazetteerFS строки countryInfo.txt, admin1CodesASCII.txt и timeZones.txt GeoNames; admin2Codes.txt отсутствует.
+var testGazetteerFS = fstest.MapFS{
+	CountriesFileName: &fstest.MapFile{Data: []byte("# GeoNames country info\n" +
+		"#ISO\tISO3\tISO-Numeric\tfips\tCountry\tCapital\tArea(in sq km)\tPopulation\tContinent\ttld\tCurrencyCode\tCurrencyName\tPhone\tPostal Code Format\tPostal Code Regex\tLanguages\tgeonameid\tneighbours\tEquivalentFipsCode\n" +
+		"RU\tRUS\t643\tRS\tRussia\tMoscow\t17100000\t144478050\tEU\t.ru\tRUB\tRuble\t7\t######\t^(\\d{6})$\tru,tt,xal,cau,ady,kv,ce,tyv,cv,udm,tut,mns,bua,myv,mdf,chm,ba,inh,kbd,krc,av,sah,nog\t2017370\tGE,CN,BY,UA,KZ,LV,PL,EE,LT,FI,MN,NO,AZ,KP\t\n")},
+	Admin1FileName: &fstest.MapFile{Data: []byte("RU.73\tTatarstan\tTatarstan\t484048\n" +
+		"RU.48\tMoscow\tMoscow\t524894\n")},
+	TimeZonesFileName: &fstest.MapFile{Data: []byte("CountryCode\tTimeZoneId\tGMT offset 1. Jan 2024\tDST offset 1. Jul 2024\trawOffset (independant of DST)\n" +
+		"RU\tEurope/Moscow\t3.0\t3.0\t3.0\n")},
+}
+
+func TestGazetteer(t *testing.T) {
+	g, err := ReadGazetteerFS(testGazetteerFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kazan := &Location{GeoNameID: 551487, Name: "Kazan", CountryCode: "RU", Admin1Code: "73", Admin2Code: "1", TimeZone: "Europe/Moscow"}
+
+	place := g.GetPlace(kazan)
+	if place.Country == nil || place.Country.Name != "Russia" || place.Country.GeoNameID != 2017370 {
+		t.Errorf("country %+v", place.Country)
+	}
+	if place.Region == nil || place.Region.Name != "Tatarstan" {
+		t.Errorf("region %+v", place.Region)
+	}
+	if place.District != nil {
+		t.Errorf("district %+v, want nil without admin2Codes.txt", place.District)
+	}
+	if place.Time == nil {
+		t.Fatal("time zone is nil")
+	}
+	if _, offset := time.Date(2024, 1, 1, 12, 0, 0, 0, place.Time).Zone(); offset != 3*3600 {
+		t.Errorf("time zone offset %d, want 3 hours", offset)
+	}
+	if unknown := g.GetTimeLocation(&Location{TimeZone: "Nowhere/Unknown"}); unknown != nil {
+		t.Errorf("unknown time zone %v, want nil", unknown)
+	}
+}
+
+func TestGazetteerDisplayName(t *testing.T) {
+	g, err := ReadGazetteerFS(testGazetteerFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kazan := &Location{GeoNameID: 551487, Name: "Kazan", CountryCode: "RU", Admin1Code: "73", AlternateNames: []string{"Казань", "Kazan"}}
+	moscow := &Location{GeoNameID: 524901, Name: "Moscow", CountryCode: "RU", Admin1Code: "48"}
+	unknown := &Location{Name: "Atlantis", CountryCode: "XX", Admin1Code: "01"}
+
+	if name := g.GetDisplayName(kazan, "en"); name != "Kazan, Tatarstan, Russia" {
+		t.Errorf("without names: %q", name)
+	}
+	// без NameResolver название объекта берётся из альтернативных названий по письменности языка
+	if name := g.GetDisplayName(kazan, "ru"); name != "Казань, Tatarstan, Russia" {
+		t.Errorf("without names in ru: %q", name)
+	}
+	if name := g.GetDisplayName(unknown, "ru"); name != "Atlantis" {
+		t.Errorf("unknown country: %q", name)
+	}
+
+	g.Names = NewNameResolver([]*AlternateName{
+		{GeoNameID: 551487, Language: "ru", Name: "Казань"},
+		{GeoNameID: 484048, Language: "ru", Name: "Татарстан"},
+		{GeoNameID: 2017370, Language: "ru", Name: "Россия"},
+		{GeoNameID: 524901, Language: "ru", Name: "Москва"},
+		{GeoNameID: 524894, Language: "ru", Name: "Москва"},
+	})
+	tests := []struct {
+		location *Location
+		language string
+		want     string
+	}{
+		{kazan, "ru", "Казань, Татарстан, Россия"},
+		// регион совпадает с названием города
+		{moscow, "ru", "Москва, Россия"},
+		// названий на немецком нет: выводятся английские
+		{kazan, "de", "Kazan, Tatarstan, Russia"},
+	}
+	for _, test := range tests {
+		if name := g.GetDisplayName(test.location, test.language); name != test.want {
+			t.Errorf("%s in %s: %q, want %q", test.location.Name, test.language, name, test.want)
+		}
+	}
+}
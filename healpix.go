@@ -0,0 +1,645 @@
+package gorewind
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// HEALPix (Hierarchical Equal Area isoLatitude Pixelization) — разбиение сферы на 12·Nside² ячеек равной площади.
+// Нумерация ячеек совпадает с HEALPix C++ и healpy, поэтому номера можно сопоставлять с внешними обзорами.
+// K. M. Górski et al. HEALPix: a Framework for High Resolution Discretization and Fast Analysis of Data
+// Distributed on the Sphere, ApJ 622, 759 (2005). https://healpix.jpl.nasa.gov/
+
+// HEALPixScheme схема нумерации ячеек HEALPix.
+type HEALPixScheme int
+
+const (
+	HEALPixRing   HEALPixScheme = iota // по кольцам постоянной широты с севера на юг
+	HEALPixNested                      // иерархическая: ячейки одной родительской ячейки идут подряд
+)
+
+// HEALPixMaxNside максимальное значение Nside.
+const HEALPixMaxNside = 1 << 29
+
+var (
+	// ErrHEALPixNside Nside вне допустимого диапазона или не является степенью двойки для схемы NESTED.
+	ErrHEALPixNside = errors.New("invalid HEALPix nside")
+	// ErrHEALPixPolygon многоугольник содержит меньше трёх вершин или не является выпуклым.
+	ErrHEALPixPolygon = errors.New("polygon must be convex and have at least 3 vertices")
+)
+
+// HEALPix разбиение сферы с заданным Nside и схемой нумерации.
+type HEALPix struct {
+	Nside  int
+	Scheme HEALPixScheme
+
+	order int // log2(Nside) или -1, если Nside не степень двойки
+	npix  int
+	ncap  int // количество ячеек в северной полярной шапке
+}
+
+// Положение граней HEALPix: номер кольца угла грани (в единицах Nside) и долгота (в единицах π/4).
+var (
+	healpixRings      = [12]int{2, 2, 2, 2, 3, 3, 3, 3, 4, 4, 4, 4}
+	healpixLongitudes = [12]int{1, 3, 5, 7, 0, 2, 4, 6, 1, 3, 5, 7}
+)
+
+// NewHEALPix создаёт разбиение; для схемы HEALPixNested nside должен быть степенью двойки.
+func NewHEALPix(nside int, scheme HEALPixScheme) (*HEALPix, error) {
+	if nside < 1 || nside > HEALPixMaxNside {
+		return nil, ErrHEALPixNside
+	}
+	order := -1
+	if nside&(nside-1) == 0 {
+		order = 0
+		for 1<<order < nside {
+			order++
+		}
+	}
+	if scheme == HEALPixNested && order < 0 {
+		return nil, ErrHEALPixNside
+	}
+	return &HEALPix{
+		Nside:  nside,
+		Scheme: scheme,
+		order:  order,
+		npix:   12 * nside * nside,
+		ncap:   2 * nside * (nside - 1),
+	}, nil
+}
+
+// Pixels возвращает количество ячеек.
+func (h *HEALPix) Pixels() int {
+	return h.npix
+}
+
+// GetPixelArea возвращает площадь ячейки в стерадианах.
+func (h *HEALPix) GetPixelArea() float64 {
+	return 4 * math.Pi / float64(h.npix)
+}
+
+// GetResolution возвращает характерный размер ячейки (корень из площади) в радианах.
+func (h *HEALPix) GetResolution() float64 {
+	return math.Sqrt(h.GetPixelArea())
+}
+
+// GetPixel возвращает номер ячейки, содержащей точку coords, или -1 для координат NaN.
+func (h *HEALPix) GetPixel(coords SphericalCoords) int {
+	if math.IsNaN(coords.Longitude.Radians()) || math.IsNaN(coords.Latitude.Radians()) {
+		return -1
+	}
+	ix, iy, face := h.getXYF(coords.Latitude.Sin, coords.Longitude.Radians())
+	return h.fromXYF(ix, iy, face)
+}
+
+// GetPixelCoords возвращает координаты центра ячейки pixel.
+func (h *HEALPix) GetPixelCoords(pixel int) SphericalCoords {
+	ix, iy, face := h.toXYF(pixel)
+	nl4 := 4 * h.Nside
+	jr := healpixRings[face]*h.Nside - ix - iy - 1
+
+	var z float64
+	var nr, kshift int
+	switch {
+	case jr < h.Nside:
+		nr = jr
+		z = 1 - float64(nr*nr)/float64(3*h.Nside*h.Nside)
+	case jr > 3*h.Nside:
+		nr = nl4 - jr
+		z = float64(nr*nr)/float64(3*h.Nside*h.Nside) - 1
+	default:
+		nr = h.Nside
+		z = float64(2*h.Nside-jr) * 2 / float64(3*h.Nside)
+		kshift = (jr - h.Nside) & 1
+	}
+	jp := (healpixLongitudes[face]*nr + ix - iy + 1 + kshift) / 2
+	if jp > nl4 {
+		jp -= nl4
+	} else if jp < 1 {
+		jp += nl4
+	}
+	longitude := (float64(jp) - float64(kshift+1)/2) * (math.Pi / 2 / float64(nr))
+	return NewSphericalCoords(longitude, math.Asin(z), 0)
+}
+
+// GetObjectPixel возвращает номер ячейки объекта item.
+func (h *HEALPix) GetObjectPixel(item Positioned) int {
+	return h.GetPixel(item.GetCoords())
+}
+
+// Bin распределяет объекты по ячейкам, например, для построения карты плотности.
+// Объекты без координат пропускаются.
+func (h *HEALPix) Bin(items []Positioned) map[int][]Positioned {
+	result := make(map[int][]Positioned)
+	for _, item := range items {
+		if hasPosition(item) {
+			pixel := h.GetObjectPixel(item)
+			result[pixel] = append(result[pixel], item)
+		}
+	}
+	return result
+}
+
+// GetNeighbours возвращает соседние ячейки в порядке SW, W, NW, N, NE, E, SE, S, как healpy.get_all_neighbours.
+// Если соседа в каком-либо направлении нет (в восьми точках, где сходятся три грани), вместо него возвращается -1.
+func (h *HEALPix) GetNeighbours(pixel int) [8]int {
+	var result [8]int
+	ix, iy, face := h.toXYF(pixel)
+	for i := range result {
+		x, y := ix+healpixXOffsets[i], iy+healpixYOffsets[i]
+		direction := 4
+		if x < 0 {
+			x += h.Nside
+			direction--
+		} else if x >= h.Nside {
+			x -= h.Nside
+			direction++
+		}
+		if y < 0 {
+			y += h.Nside
+			direction -= 3
+		} else if y >= h.Nside {
+			y -= h.Nside
+			direction += 3
+		}
+
+		f := healpixFaces[direction][face]
+		if f < 0 {
+			result[i] = -1
+			continue
+		}
+		bits := healpixSwaps[direction][face>>2]
+		if bits&1 != 0 {
+			x = h.Nside - x - 1
+		}
+		if bits&2 != 0 {
+			y = h.Nside - y - 1
+		}
+		if bits&4 != 0 {
+			x, y = y, x
+		}
+		result[i] = h.fromXYF(x, y, f)
+	}
+	return result
+}
+
+// Смещения соседей внутри грани и переходы между гранями для GetNeighbours (из HEALPix C++).
+var (
+	healpixXOffsets = [8]int{-1, -1, 0, 1, 1, 1, 0, -1}
+	healpixYOffsets = [8]int{0, 1, 1, 1, 0, -1, -1, -1}
+	healpixFaces    = [9][12]int{
+		{8, 9, 10, 11, -1, -1, -1, -1, 10, 11, 8, 9}, // S
+		{5, 6, 7, 4, 8, 9, 10, 11, 9, 10, 11, 8},     // SE
+		{-1, -1, -1, -1, 5, 6, 7, 4, -1, -1, -1, -1}, // E
+		{4, 5, 6, 7, 11, 8, 9, 10, 11, 8, 9, 10},     // SW
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},       // центр
+		{1, 2, 3, 0, 0, 1, 2, 3, 5, 6, 7, 4},         // NE
+		{-1, -1, -1, -1, 7, 4, 5, 6, -1, -1, -1, -1}, // W
+		{3, 0, 1, 2, 3, 0, 1, 2, 4, 5, 6, 7},         // NW
+		{2, 3, 0, 1, -1, -1, -1, -1, 0, 1, 2, 3},     // N
+	}
+	healpixSwaps = [9][3]int{
+		{0, 0, 3}, // S
+		{0, 0, 6}, // SE
+		{0, 0, 0}, // E
+		{0, 0, 5}, // SW
+		{0, 0, 0}, // центр
+		{5, 0, 0}, // NE
+		{0, 0, 0}, // W
+		{6, 0, 0}, // NW
+		{3, 0, 0}, // N
+	}
+)
+
+// QueryDisc возвращает ячейки, центры которых находятся на угловом расстоянии не больше radius (в радианах) от coords.
+// Если inclusive, возвращаются все ячейки, пересекающие круг; как и в healpy, в результат могут попасть
+// и несколько ячеек, лежащих рядом с кругом. Номера возвращаются по возрастанию.
+func (h *HEALPix) QueryDisc(coords SphericalCoords, radius float64, inclusive bool) []int {
+	if inclusive {
+		radius += h.getMaxPixelRadius()
+	}
+	if radius >= math.Pi {
+		return h.getAllPixels()
+	}
+	if radius < 0 {
+		return nil
+	}
+
+	z0 := coords.Latitude.Sin
+	sin0 := coords.Latitude.Cos
+	cosRadius := math.Cos(radius)
+	colatitude := math.Pi/2 - coords.Latitude.Radians()
+	zMax, zMin := math.Cos(math.Max(colatitude-radius, 0)), math.Cos(math.Min(colatitude+radius, math.Pi))
+
+	var result []int
+	for ring := 1; ring < 4*h.Nside; ring++ {
+		start, count, z, shifted := h.getRing(ring)
+		if z < zMin || z > zMax {
+			continue
+		}
+		// cos расстояния до центров кольца: z·z0 + sinθ·sinθ0·cos(Δφ) ≥ cos(radius)
+		sin := math.Sqrt(math.Max(0, 1-z*z))
+		var width float64
+		if sin*sin0 == 0 {
+			if z*z0 < cosRadius {
+				continue
+			}
+			width = math.Pi
+		} else {
+			x := (cosRadius - z*z0) / (sin * sin0)
+			if x > 1 {
+				continue
+			}
+			width = math.Acos(math.Max(x, -1))
+		}
+		result = h.appendRingPixels(result, start, count, shifted, coords.Longitude.Radians(), width)
+	}
+	return h.getSortedPixels(result)
+}
+
+// QueryPolygon возвращает ячейки, центры которых находятся внутри выпуклого многоугольника vertices
+// со сторонами по дугам больших кругов. Если inclusive, возвращаются все ячейки, пересекающие многоугольник,
+// и, возможно, несколько соседних с ним. Номера возвращаются по возрастанию.
+func (h *HEALPix) QueryPolygon(vertices []SphericalCoords, inclusive bool) ([]int, error) {
+	if len(vertices) < 3 {
+		return nil, ErrHEALPixPolygon
+	}
+	points := make([][3]float64, len(vertices))
+	var center [3]float64
+	for i, vertex := range vertices {
+		points[i] = getUnitVector(vertex)
+		for axis := range center {
+			center[axis] += points[i][axis]
+		}
+	}
+
+	// нормали сторон, направленные внутрь многоугольника
+	normals := make([][3]float64, len(points))
+	for i := range points {
+		normals[i] = getCrossProduct(points[i], points[(i+1)%len(points)])
+		if length := math.Sqrt(getDotProduct(normals[i], normals[i])); length > 0 {
+			for axis := range normals[i] {
+				normals[i][axis] /= length
+			}
+		}
+	}
+	sign := 0.0
+	for i, normal := range normals {
+		for j, point := range points {
+			if j == i || j == (i+1)%len(points) {
+				continue
+			}
+			product := getDotProduct(normal, point)
+			if sign == 0 && math.Abs(product) > 1e-12 {
+				sign = math.Copysign(1, product)
+			}
+			if product*sign < -1e-12 {
+				return nil, ErrHEALPixPolygon
+			}
+		}
+	}
+	if sign == 0 {
+		return nil, ErrHEALPixPolygon
+	}
+	for i := range normals {
+		for axis := range normals[i] {
+			normals[i][axis] *= sign
+		}
+	}
+
+	// кандидаты — ячейки круга, описанного вокруг вершин
+	margin := 0.0
+	if inclusive {
+		margin = math.Sin(h.getMaxPixelRadius())
+	}
+	var candidates []int
+	if length := math.Sqrt(getDotProduct(center, center)); length > 1e-12 {
+		centerCoords := NewSphericalCoords(math.Atan2(center[1], center[0]), math.Asin(center[2]/length), 0)
+		radius := 0.0
+		for _, vertex := range vertices {
+			radius = math.Max(radius, centerCoords.GetDistance(vertex))
+		}
+		if radius < math.Pi/2 {
+			candidates = h.QueryDisc(centerCoords, radius, inclusive)
+		}
+	}
+	if candidates == nil {
+		candidates = h.getAllPixels()
+	}
+
+	result := candidates[:0]
+	for _, pixel := range candidates {
+		point := getUnitVector(h.GetPixelCoords(pixel))
+		inside := true
+		for _, normal := range normals {
+			if getDotProduct(normal, point) < -margin {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			result = append(result, pixel)
+		}
+	}
+	return result, nil
+}
+
+// NestToRing переводит номер ячейки из схемы NESTED в схему RING.
+// Схема NESTED определена только для nside, равного степени двойки; для остальных возвращается -1.
+func (h *HEALPix) NestToRing(pixel int) int {
+	if h.order < 0 {
+		return -1
+	}
+	ix, iy, face := h.nestToXYF(pixel)
+	return h.xyfToRing(ix, iy, face)
+}
+
+// RingToNest переводит номер ячейки из схемы RING в схему NESTED.
+// Схема NESTED определена только для nside, равного степени двойки; для остальных возвращается -1.
+func (h *HEALPix) RingToNest(pixel int) int {
+	if h.order < 0 {
+		return -1
+	}
+	ix, iy, face := h.ringToXYF(pixel)
+	return h.xyfToNest(ix, iy, face)
+}
+
+// getXYF возвращает координаты ячейки внутри грани и номер грани для точки с z = sin(широта) и долготой phi.
+func (h *HEALPix) getXYF(z, phi float64) (int, int, int) {
+	nside := h.Nside
+	za := math.Abs(z)
+	tt := math.Mod(phi*2/math.Pi, 4)
+	if tt < 0 {
+		tt += 4
+	}
+
+	if za <= 2.0/3 {
+		// экваториальная зона
+		temp1 := float64(nside) * (0.5 + tt)
+		temp2 := float64(nside) * z * 0.75
+		jp := int(temp1 - temp2) // номер восходящей линии
+		jm := int(temp1 + temp2) // номер нисходящей линии
+		ifp, ifm := jp/nside, jm/nside
+		var face int
+		switch {
+		case ifp == ifm:
+			face = ifp | 4
+		case ifp < ifm:
+			face = ifp
+		default:
+			face = ifm + 8
+		}
+		return jm % nside, nside - jp%nside - 1, face
+	}
+
+	// полярные шапки
+	ntt := int(tt)
+	if ntt > 3 {
+		ntt = 3
+	}
+	tp := tt - float64(ntt)
+	tmp := float64(nside) * math.Sqrt(3*(1-za))
+	jp := int(tp * tmp)
+	jm := int((1 - tp) * tmp)
+	if jp > nside-1 {
+		jp = nside - 1
+	}
+	if jm > nside-1 {
+		jm = nside - 1
+	}
+	if z >= 0 {
+		return nside - jm - 1, nside - jp - 1, ntt
+	}
+	return jp, jm, ntt + 8
+}
+
+func (h *HEALPix) fromXYF(ix, iy, face int) int {
+	if h.Scheme == HEALPixNested {
+		return h.xyfToNest(ix, iy, face)
+	}
+	return h.xyfToRing(ix, iy, face)
+}
+
+func (h *HEALPix) toXYF(pixel int) (int, int, int) {
+	if h.Scheme == HEALPixNested {
+		return h.nestToXYF(pixel)
+	}
+	return h.ringToXYF(pixel)
+}
+
+func (h *HEALPix) xyfToNest(ix, iy, face int) int {
+	return face<<(2*h.order) + spreadBits(ix) + spreadBits(iy)<<1
+}
+
+func (h *HEALPix) nestToXYF(pixel int) (int, int, int) {
+	face := pixel >> (2 * h.order)
+	pixel &= 1<<(2*h.order) - 1
+	return compressBits(pixel), compressBits(pixel >> 1), face
+}
+
+func (h *HEALPix) xyfToRing(ix, iy, face int) int {
+	nl4 := 4 * h.Nside
+	jr := healpixRings[face]*h.Nside - ix - iy - 1
+
+	var nr, before, kshift int
+	switch {
+	case jr < h.Nside:
+		nr = jr
+		before = 2 * nr * (nr - 1)
+	case jr > 3*h.Nside:
+		nr = nl4 - jr
+		before = h.npix - 2*(nr+1)*nr
+	default:
+		nr = h.Nside
+		before = h.ncap + (jr-h.Nside)*nl4
+		kshift = (jr - h.Nside) & 1
+	}
+	jp := (healpixLongitudes[face]*nr + ix - iy + 1 + kshift) / 2
+	if jp > nl4 {
+		jp -= nl4
+	} else if jp < 1 {
+		jp += nl4
+	}
+	return before + jp - 1
+}
+
+func (h *HEALPix) ringToXYF(pixel int) (int, int, int) {
+	nside := h.Nside
+	nl2 := 2 * nside
+	var ring, phi, kshift, nr, face int
+	switch {
+	case pixel < h.ncap: // северная полярная шапка
+		ring = (1 + isqrt(1+2*pixel)) >> 1
+		phi = pixel + 1 - 2*ring*(ring-1)
+		nr = ring
+		face = (phi - 1) / nr
+	case pixel < h.npix-h.ncap: // экваториальная зона
+		ip := pixel - h.ncap
+		tmp := ip / (4 * nside)
+		ring = tmp + nside
+		phi = ip - tmp*4*nside + 1
+		kshift = (ring + nside) & 1
+		nr = nside
+		ire := tmp + 1
+		irm := nl2 + 2 - ire
+		ifm := (phi - ire/2 + nside - 1) / nside
+		ifp := (phi - irm/2 + nside - 1) / nside
+		switch {
+		case ifp == ifm:
+			face = ifp | 4
+		case ifp < ifm:
+			face = ifp
+		default:
+			face = ifm + 8
+		}
+	default: // южная полярная шапка
+		ip := h.npix - pixel
+		ring = (1 + isqrt(2*ip-1)) >> 1
+		phi = 4*ring + 1 - (ip - 2*ring*(ring-1))
+		nr = ring
+		ring = 2*nl2 - ring
+		face = 8 + (phi-1)/nr
+	}
+	irt := ring - healpixRings[face]*nside + 1
+	ipt := 2*phi - healpixLongitudes[face]*nr - kshift - 1
+	if ipt >= nl2 {
+		ipt -= 8 * nside
+	}
+	return (ipt - irt) >> 1, (-ipt - irt) >> 1, face
+}
+
+// getRing возвращает первую ячейку (в схеме RING), количество ячеек, z = sin(широта) центров
+// и признак смещения центров на половину ячейки для кольца ring с 1.
+func (h *HEALPix) getRing(ring int) (start, count int, z float64, shifted bool) {
+	north := ring
+	if ring > 2*h.Nside {
+		north = 4*h.Nside - ring
+	}
+	if north < h.Nside {
+		z = 1 - float64(north*north)/float64(3*h.Nside*h.Nside)
+		count = 4 * north
+		shifted = true
+		start = 2 * north * (north - 1)
+	} else {
+		z = float64(2*h.Nside-north) * 2 / float64(3*h.Nside)
+		count = 4 * h.Nside
+		shifted = (north-h.Nside)&1 == 0
+		start = h.ncap + (north-h.Nside)*count
+	}
+	if north != ring {
+		z = -z
+		start = h.npix - start - count
+	}
+	return start, count, z, shifted
+}
+
+// appendRingPixels добавляет ячейки кольца, центры которых отстоят по долготе от phi не больше чем на width.
+func (h *HEALPix) appendRingPixels(result []int, start, count int, shifted bool, phi, width float64) []int {
+	if width >= math.Pi {
+		for i := 0; i < count; i++ {
+			result = append(result, h.fromRing(start+i))
+		}
+		return result
+	}
+	step := 2 * math.Pi / float64(count)
+	offset := 0.0
+	if shifted {
+		offset = 0.5
+	}
+	first := int(math.Ceil((phi-width)/step - offset))
+	last := int(math.Floor((phi+width)/step - offset))
+	if last-first+1 >= count {
+		last = first + count - 1
+	}
+	for i := first; i <= last; i++ {
+		j := i % count
+		if j < 0 {
+			j += count
+		}
+		result = append(result, h.fromRing(start+j))
+	}
+	return result
+}
+
+func (h *HEALPix) fromRing(pixel int) int {
+	if h.Scheme == HEALPixNested {
+		return h.RingToNest(pixel)
+	}
+	return pixel
+}
+
+// getMaxPixelRadius возвращает наибольшее угловое расстояние от центра ячейки до её углов.
+func (h *HEALPix) getMaxPixelRadius() float64 {
+	t := 1 - 1/float64(h.Nside)
+	t *= t
+	a := NewSphericalCoords(math.Pi/float64(4*h.Nside), math.Asin(2.0/3), 0)
+	b := NewSphericalCoords(0, math.Asin(1-t/3), 0)
+	return a.GetDistance(b)
+}
+
+func (h *HEALPix) getAllPixels() []int {
+	result := make([]int, h.npix)
+	for i := range result {
+		result[i] = i
+	}
+	return result
+}
+
+func (h *HEALPix) getSortedPixels(pixels []int) []int {
+	sort.Ints(pixels)
+	result := pixels[:0]
+	for i, pixel := range pixels {
+		if i == 0 || pixel != pixels[i-1] {
+			result = append(result, pixel)
+		}
+	}
+	return result
+}
+
+// spreadBits раздвигает биты числа: бит i переходит в бит 2i.
+func spreadBits(v int) int {
+	var result int
+	for bit := 0; v>>bit != 0; bit++ {
+		result |= (v >> bit & 1) << (2 * bit)
+	}
+	return result
+}
+
+// compressBits собирает чётные биты числа: бит 2i переходит в бит i.
+func compressBits(v int) int {
+	var result int
+	for bit := 0; v>>(2*bit) != 0; bit++ {
+		result |= (v >> (2 * bit) & 1) << bit
+	}
+	return result
+}
+
+// isqrt возвращает целую часть квадратного корня.
+func isqrt(v int) int {
+	result := int(math.Sqrt(float64(v)))
+	for result*result > v {
+		result--
+	}
+	for (result+1)*(result+1) <= v {
+		result++
+	}
+	return result
+}
+
+func getCrossProduct(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func getDotProduct(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
@@ -0,0 +1,96 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+// Эталоны — значения healpy.pix2ang и healpy.ang2pix; θ = π/2 − широта.
+func TestHEALPixPixelCoords(t *testing.T) {
+	tests := []struct {
+		nside      int
+		scheme     HEALPixScheme
+		pixel      int
+		theta, phi float64
+	}{
+		{1, HEALPixRing, 0, 0.8410686705679303, math.Pi / 4},
+		{1, HEALPixRing, 4, math.Pi / 2, 0},
+		{1, HEALPixRing, 11, 2.300523983021863, 7 * math.Pi / 4},
+		{2, HEALPixRing, 0, 0.41113786232234784, math.Pi / 4},
+		{2, HEALPixNested, 0, 1.2309594173407747, math.Pi / 4},
+		{2, HEALPixNested, 3, 0.41113786232234784, math.Pi / 4},
+	}
+	for _, test := range tests {
+		h, err := NewHEALPix(test.nside, test.scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		coords := h.GetPixelCoords(test.pixel)
+		if theta := math.Pi/2 - coords.Latitude.Radians(); math.Abs(theta-test.theta) > 1e-12 ||
+			math.Abs(coords.Longitude.Radians()-test.phi) > 1e-12 {
+			t.Errorf("nside %d scheme %d pixel %d: θ %v φ %v, want %v, %v",
+				test.nside, test.scheme, test.pixel, theta, coords.Longitude.Radians(), test.theta, test.phi)
+		}
+		if pixel := h.GetPixel(coords); pixel != test.pixel {
+			t.Errorf("nside %d scheme %d: GetPixel %d, want %d", test.nside, test.scheme, pixel, test.pixel)
+		}
+	}
+}
+
+func TestHEALPixGetPixel(t *testing.T) {
+	tests := []struct {
+		nside      int
+		scheme     HEALPixScheme
+		theta, phi float64
+		want       int
+	}{
+		{1, HEALPixRing, 0.0001, 0.1, 0},
+		{1, HEALPixNested, 0.0001, 0.1, 0},
+		{1, HEALPixRing, math.Pi - 0.0001, 0.1, 8},
+		{16, HEALPixRing, math.Pi / 2, 0.001, 1504},
+	}
+	for _, test := range tests {
+		h, err := NewHEALPix(test.nside, test.scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pixel := h.GetPixel(NewSphericalCoords(test.phi, math.Pi/2-test.theta, 0)); pixel != test.want {
+			t.Errorf("nside %d scheme %d θ %v φ %v: %d, want %d", test.nside, test.scheme, test.theta, test.phi, pixel, test.want)
+		}
+	}
+}
+
+func TestHEALPixRoundTrip(t *testing.T) {
+	for _, scheme := range []HEALPixScheme{HEALPixRing, HEALPixNested} {
+		for _, nside := range []int{1, 2, 8, 64} {
+			h, err := NewHEALPix(nside, scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for pixel := 0; pixel < h.Pixels(); pixel++ {
+				if got := h.GetPixel(h.GetPixelCoords(pixel)); got != pixel {
+					t.Fatalf("nside %d scheme %d: pixel %d maps to %d", nside, scheme, pixel, got)
+				}
+				if got := h.RingToNest(h.NestToRing(pixel)); got != pixel {
+					t.Fatalf("nside %d: nest-ring round trip %d → %d", nside, pixel, got)
+				}
+			}
+		}
+	}
+}
+
+func TestHEALPixNestedConversionNonPowerOfTwo(t *testing.T) {
+	h, err := NewHEALPix(3, HEALPixRing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pixel := h.NestToRing(0); pixel != -1 {
+		t.Errorf("NestToRing: %d, want -1", pixel)
+	}
+	if pixel := h.RingToNest(0); pixel != -1 {
+		t.Errorf("RingToNest: %d, want -1", pixel)
+	}
+	if _, err := NewHEALPix(3, HEALPixNested); err != ErrHEALPixNside {
+		t.Errorf("NewHEALPix nested: %v, want ErrHEALPixNside", err)
+	}
+}
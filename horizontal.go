@@ -0,0 +1,99 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import (
+	"math"
+	"time"
+)
+
+// Горизонтальные координаты хранятся в SphericalCoords: долгота — азимут, отсчитываемый от севера через восток,
+// широта — высота над горизонтом. Экваториальные координаты отнесены к истинному экватору и равноденствию даты.
+
+// Refraction условия атмосферы для расчёта рефракции по формулам Bennett и Saemundsson.
+type Refraction struct {
+	Pressure    float64 // давление в гПа
+	Temperature float64 // температура в °C
+}
+
+// StandardRefraction стандартные условия, для которых составлены формулы рефракции.
+var StandardRefraction = Refraction{Pressure: 1010, Temperature: 10}
+
+// minRefractionAltitude высота, ниже которой рефракция не учитывается: формулы там неприменимы.
+const minRefractionAltitude = -2 * Degree
+
+// GetHorizontal возвращает горизонтальные координаты в месте l в момент t для экваториальных координат coords.
+func (l *Location) GetHorizontal(coords SphericalCoords, t time.Time) SphericalCoords {
+	return getHorizontal(coords, l.GetApparentSiderealTime(t).float64, l.Coords.Latitude.float64)
+}
+
+// GetEquatorialFromHorizontal возвращает экваториальные координаты для горизонтальных координат coords
+// в месте l в момент t; обратное преобразование к GetHorizontal.
+func (l *Location) GetEquatorialFromHorizontal(coords SphericalCoords, t time.Time) SphericalCoords {
+	return getEquatorialFromHorizontal(coords, l.GetApparentSiderealTime(t).float64, l.Coords.Latitude.float64)
+}
+
+// GetParallacticAngle возвращает параллактический угол — угол между направлениями на полюс мира и на зенит
+// для экваториальных координат coords в месте l в момент t, отрицательный к востоку от меридиана.
+func (l *Location) GetParallacticAngle(coords SphericalCoords, t time.Time) Angle {
+	hourAngle := l.GetApparentSiderealTime(t).float64 - coords.Longitude.float64
+	latitude := l.Coords.Latitude
+	return newAngle(math.Atan2(math.Sin(hourAngle),
+		latitude.Sin/latitude.Cos*coords.Latitude.Cos-coords.Latitude.Sin*math.Cos(hourAngle)))
+}
+
+// GetHorizontalCoords возвращает горизонтальные координаты видимого места объекта в месте l в момент t.
+// Если refraction не nil, высота исправляется за рефракцию.
+func (ao *AstronomicalObject) GetHorizontalCoords(l *Location, t time.Time, refraction *Refraction) SphericalCoords {
+	coords := l.GetHorizontal(ao.GetApparentCoords(NewEpoch(t)), t)
+	if refraction != nil {
+		coords.Latitude = newAngle(refraction.GetApparentAltitude(coords.Latitude.float64))
+	}
+	return coords
+}
+
+// GetApparentAltitude возвращает видимую высоту (в радианах) для истинной высоты altitude по формуле Saemundsson.
+func (r Refraction) GetApparentAltitude(altitude float64) float64 {
+	if altitude < minRefractionAltitude {
+		return altitude
+	}
+	h := altitude * Radian
+	minutes := 1.02 / math.Tan((h+10.3/(h+5.11))*Degree)
+	return altitude + r.getFactor()*minutes/60*Degree
+}
+
+// GetTrueAltitude возвращает истинную высоту (в радианах) для видимой высоты altitude по формуле Bennett.
+func (r Refraction) GetTrueAltitude(altitude float64) float64 {
+	if altitude < minRefractionAltitude {
+		return altitude
+	}
+	h := altitude * Radian
+	minutes := 1 / math.Tan((h+7.31/(h+4.4))*Degree)
+	// поправка Meeus уточняет формулу до 0.015′
+	minutes -= 0.06 * math.Sin((14.7*minutes+13)*Degree)
+	return altitude - r.getFactor()*minutes/60*Degree
+}
+
+// getFactor возвращает множитель рефракции для давления и температуры, отличных от стандартных.
+func (r Refraction) getFactor() float64 {
+	return r.Pressure / 1010 * 283 / (273 + r.Temperature)
+}
+
+// getHorizontal возвращает горизонтальные координаты для экваториальных координат coords,
+// местного звёздного времени localTime и широты места latitude (в радианах).
+func getHorizontal(coords SphericalCoords, localTime, latitude float64) SphericalCoords {
+	// оси после поворотов: восток, север, зенит; азимут отсчитывается в обратную сторону
+	horizontal := coords.GetOffset(-localTime-math.Pi/2, math.Pi/2-latitude, 3*math.Pi/2)
+	horizontal.Longitude = newAngle(normalizeLongitude(-horizontal.Longitude.float64))
+	return horizontal
+}
+
+// getEquatorialFromHorizontal возвращает экваториальные координаты для горизонтальных координат coords,
+// местного звёздного времени localTime и широты места latitude (в радианах).
+func getEquatorialFromHorizontal(coords SphericalCoords, localTime, latitude float64) SphericalCoords {
+	coords.Longitude = newAngle(normalizeLongitude(-coords.Longitude.float64))
+	return coords.GetOffset(-3*math.Pi/2, latitude-math.Pi/2, localTime+math.Pi/2)
+}
@@ -0,0 +1,73 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Meeus, пример 13.b: Венера в Вашингтоне; азимут в примере отсчитан от юга, 68.0337° + 180°.
+func TestGetHorizontal(t *testing.T) {
+	washington := &Location{Coords: NewSphericalCoords(-NewAngleFromDegrees(77, 3, 56).Radians(), NewAngleFromDegrees(38, 55, 17).Radians(), 0)}
+	moment := time.Date(1987, 4, 10, 19, 21, 0, 0, time.UTC)
+	venus := NewClockCoords(23, 9, 16.641, -6, 43, 11.61)
+
+	horizontal := washington.GetHorizontal(venus, moment)
+	if math.Abs(horizontal.Longitude.Degrees()-248.0337) > 0.001 || math.Abs(horizontal.Latitude.Degrees()-15.1249) > 0.001 {
+		t.Errorf("azimuth %.4f°, altitude %.4f°, want 248.0337°, 15.1249°", horizontal.Longitude.Degrees(), horizontal.Latitude.Degrees())
+	}
+
+	back := washington.GetEquatorialFromHorizontal(horizontal, moment)
+	if math.Abs(back.Longitude.Radians()-venus.Longitude.Radians()) > 1e-12 ||
+		math.Abs(back.Latitude.Radians()-venus.Latitude.Radians()) > 1e-12 {
+		t.Errorf("round trip %v, want %v", back, venus)
+	}
+}
+
+func TestRefraction(t *testing.T) {
+	tests := []struct {
+		name      string
+		altitude  func(float64) float64
+		input     float64 // градусы
+		minutes   float64 // рефракция в минутах дуги
+		tolerance float64
+	}{
+		// у горизонта видимое положение выше истинного примерно на 34.5′ (Bennett) и на 29′ для истинной высоты 0°
+		{"Bennett at horizon", StandardRefraction.GetTrueAltitude, 0, -34.5, 0.1},
+		{"Saemundsson at horizon", StandardRefraction.GetApparentAltitude, 0, 29.0, 0.1},
+		{"Bennett at 45°", StandardRefraction.GetTrueAltitude, 45, -1.0, 0.05},
+		{"no air", Refraction{Pressure: 0, Temperature: 10}.GetApparentAltitude, 0, 0, 1e-12},
+		{"below limit", StandardRefraction.GetApparentAltitude, -5, 0, 1e-12},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			refraction := (test.altitude(test.input*Degree) - test.input*Degree) / Degree * 60
+			if math.Abs(refraction-test.minutes) > test.tolerance {
+				t.Errorf("refraction %.3f′, want %.3f′", refraction, test.minutes)
+			}
+		})
+	}
+
+	// формулы Bennett и Saemundsson согласованы между собой в пределах 0.1′
+	for _, altitude := range []float64{0, 5, 30, 80} {
+		trueAltitude := StandardRefraction.GetTrueAltitude(altitude * Degree)
+		if back := StandardRefraction.GetApparentAltitude(trueAltitude); math.Abs(back/Degree-altitude)*60 > 0.1 {
+			t.Errorf("%v°: round trip %.4f°", altitude, back/Degree)
+		}
+	}
+}
+
+func TestGetHorizontalCoordsRefraction(t *testing.T) {
+	location := &Location{Coords: NewCoordsFromDegrees(37.6, 55.75)}
+	moment := time.Date(2021, 1, 15, 21, 0, 0, 0, time.UTC) // Сириус у меридиана на высоте 17°
+	star := &AstronomicalObject{Coords: NewCoordsFromDegrees(101.287, -16.716)}
+	geometric := star.GetHorizontalCoords(location, moment, nil)
+	refracted := star.GetHorizontalCoords(location, moment, &StandardRefraction)
+	if refracted.Longitude.Radians() != geometric.Longitude.Radians() {
+		t.Errorf("refraction changed azimuth")
+	}
+	want := StandardRefraction.GetApparentAltitude(geometric.Latitude.Radians())
+	if math.Abs(refracted.Latitude.Radians()-want) > 1e-12 || refracted.Latitude.Radians() <= geometric.Latitude.Radians() {
+		t.Errorf("altitude %v, want %v", refracted.Latitude.Degrees(), want/Degree)
+	}
+}
@@ -0,0 +1,191 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import "math"
+
+// Пространственное движение звезды, как в функции iauStarpm библиотеки SOFA: положение и скорость переводятся
+// в декартовы координаты, звезда движется прямолинейно и равномерно, а время распространения света от звезды
+// учитывается при обеих эпохах. Как и в iauStarpv, скорость ограничивается половиной скорости света,
+// а наблюдаемые лучевая скорость и собственное движение, искажённые эффектом Доплера, переводятся в скорость
+// относительно барицентра: для звезды Барнарда за 26 лет это меняет положение на 0.1″.
+// Для звёзд без параллакса расстояние и время распространения света неизвестны, поэтому направление
+// меняется линейно по собственному движению, а лучевая скорость не учитывается, как в iauPmpx.
+
+const (
+	// kilometresPerSecond скорость 1 км/с в астрономических единицах в юлианский год.
+	kilometresPerSecond = 365.25 * secondsPerDay / 149597870.7
+	// julianYear юлианский год в сутках.
+	julianYear = 365.25
+	// lightSpeed скорость света в астрономических единицах в юлианский год.
+	lightSpeed = SpeedOfLight * julianYear
+	// maxSpeed наибольшая скорость звезды в долях скорости света, как VMAX в iauStarpv.
+	maxSpeed = 0.5
+)
+
+// SpaceMotion пространственное движение звезды; нулевые значения означают, что величина неизвестна.
+type SpaceMotion struct {
+	ProperMotionRA  float64 // собственное движение по прямому восхождению μα·cos δ, угловые секунды в год
+	ProperMotionDec float64 // собственное движение по склонению, угловые секунды в год
+	Parallax        float64 // параллакс в угловых секундах
+	RadialVelocity  float64 // лучевая скорость, км/с, положительная при удалении
+}
+
+// GetMoved возвращает положение звезды с пространственным движением motion на эпоху to и движение на эту эпоху
+// для положения c на эпоху from. Радиус результата — расстояние в астрономических единицах,
+// если параллакс известен, иначе радиус c. Равноденствие координат не меняется: это не прецессия.
+func (c SphericalCoords) GetMoved(motion SpaceMotion, from, to Epoch) (SphericalCoords, SpaceMotion) {
+	years := float64(to-from) / julianYear
+	if motion.Parallax <= 0 {
+		// направление единичной длины, скорость — собственное движение в радианах в год
+		position, velocity := getSpaceState(c, SpaceMotion{
+			ProperMotionRA:  motion.ProperMotionRA,
+			ProperMotionDec: motion.ProperMotionDec,
+		}, 1)
+		position = getMovedPosition(position, velocity, years)
+		coords := getVectorCoords(position, c.Radius)
+		result := getSpaceMotion(coords, position, velocity)
+		result.Parallax = 0
+		result.RadialVelocity = motion.RadialVelocity
+		return coords, result
+	}
+
+	position, velocity := getSpaceState(c, motion, 1/(motion.Parallax*ArcSecond))
+	if v2 := getDotProduct(velocity, velocity); v2 > maxSpeed*maxSpeed*lightSpeed*lightSpeed {
+		scale := maxSpeed * lightSpeed / math.Sqrt(v2)
+		for axis := range velocity {
+			velocity[axis] *= scale
+		}
+	}
+	velocity = getInertialVelocity(position, velocity)
+
+	// свет, наблюдаемый в эпоху from, покинул звезду раньше на время распространения
+	emitted := math.Sqrt(getDotProduct(position, position)) / lightSpeed
+	moved := getMovedPosition(position, velocity, years+emitted)
+	// время распространения света в эпоху to решает квадратное уравнение |p + v·(t − τ)| = c·τ
+	rv := getDotProduct(moved, velocity)
+	v2 := getDotProduct(velocity, velocity)
+	c2mv2 := lightSpeed*lightSpeed - v2
+	arrived := (-rv + math.Sqrt(rv*rv+c2mv2*getDotProduct(moved, moved))) / c2mv2
+	position = getMovedPosition(position, velocity, years+emitted-arrived)
+
+	coords := getVectorCoords(position, math.Sqrt(getDotProduct(position, position)))
+	return coords, getSpaceMotion(coords, position, getObservedVelocity(position, velocity))
+}
+
+// GetCoordsAt возвращает положение объекта на эпоху epoch с учётом его пространственного движения;
+// координаты объекта отнесены к эпохе J2000, как в каталоге BSC.
+func (ao *AstronomicalObject) GetCoordsAt(epoch Epoch) SphericalCoords {
+	if ao.Motion == (SpaceMotion{}) {
+		return ao.Coords
+	}
+	coords, _ := ao.Coords.GetMoved(ao.Motion, J2000, epoch)
+	return coords
+}
+
+// getSpaceState возвращает положение (а.е.) и скорость (а.е. в юлианский год) звезды
+// на расстоянии distance (а.е.) в направлении c.
+func getSpaceState(c SphericalCoords, motion SpaceMotion, distance float64) (position, velocity [3]float64) {
+	direction := getUnitVector(c)
+	east := [3]float64{-c.Longitude.Sin, c.Longitude.Cos, 0}
+	north := [3]float64{-c.Latitude.Sin * c.Longitude.Cos, -c.Latitude.Sin * c.Longitude.Sin, c.Latitude.Cos}
+	tangential := distance * ArcSecond
+	radial := motion.RadialVelocity * kilometresPerSecond
+	for axis := range position {
+		position[axis] = distance * direction[axis]
+		velocity[axis] = tangential*(motion.ProperMotionRA*east[axis]+motion.ProperMotionDec*north[axis]) +
+			radial*direction[axis]
+	}
+	return position, velocity
+}
+
+// getSpaceMotion возвращает собственное движение, параллакс и лучевую скорость звезды
+// в направлении c с положением position и скоростью velocity.
+func getSpaceMotion(c SphericalCoords, position, velocity [3]float64) SpaceMotion {
+	distance := math.Sqrt(getDotProduct(position, position))
+	east := [3]float64{-c.Longitude.Sin, c.Longitude.Cos, 0}
+	north := [3]float64{-c.Latitude.Sin * c.Longitude.Cos, -c.Latitude.Sin * c.Longitude.Sin, c.Latitude.Cos}
+	tangential := distance * ArcSecond
+	return SpaceMotion{
+		ProperMotionRA:  getDotProduct(velocity, east) / tangential,
+		ProperMotionDec: getDotProduct(velocity, north) / tangential,
+		Parallax:        1 / distance / ArcSecond,
+		RadialVelocity:  getDotProduct(velocity, position) / distance / kilometresPerSecond,
+	}
+}
+
+// getMovedPosition возвращает положение после равномерного движения со скоростью velocity в течение years лет.
+func getMovedPosition(position, velocity [3]float64, years float64) [3]float64 {
+	for axis := range position {
+		position[axis] += velocity[axis] * years
+	}
+	return position
+}
+
+// getInertialVelocity возвращает скорость звезды в положении position относительно барицентра
+// по наблюдаемой скорости velocity, как в iauStarpv. Уравнение решается последовательными приближениями.
+func getInertialVelocity(position, velocity [3]float64) [3]float64 {
+	direction, radial, tangential := getVelocityComponents(position, velocity)
+	betaRadial := radial / lightSpeed
+	betaTangential := math.Sqrt(getDotProduct(tangential, tangential)) / lightSpeed
+
+	br, bt := betaRadial, betaTangential
+	var d, delta, previousD, previousDelta, changeD, changeDelta float64
+	for i := 0; i < 100; i++ {
+		d = 1 + br
+		w := br*br + bt*bt
+		delta = -w / (math.Sqrt(1-w) + 1)
+		br = d*betaRadial + delta
+		bt = d * betaTangential
+		if i > 0 {
+			// приближения перестают сходиться из-за ошибок округления
+			dd, ddelta := math.Abs(d-previousD), math.Abs(delta-previousDelta)
+			if i > 1 && dd >= changeD && ddelta >= changeDelta {
+				break
+			}
+			changeD, changeDelta = dd, ddelta
+		}
+		previousD, previousDelta = d, delta
+	}
+
+	scale := 1.0
+	if betaRadial != 0 {
+		scale = d + delta/betaRadial
+	}
+	for axis := range velocity {
+		velocity[axis] = direction[axis]*radial*scale + tangential[axis]*d
+	}
+	return velocity
+}
+
+// getObservedVelocity возвращает наблюдаемую скорость звезды в положении position по скорости velocity
+// относительно барицентра; обратное преобразование к getInertialVelocity, как в iauPvstar.
+func getObservedVelocity(position, velocity [3]float64) [3]float64 {
+	direction, radial, tangential := getVelocityComponents(position, velocity)
+	betaRadial := radial / lightSpeed
+	betaTangential := math.Sqrt(getDotProduct(tangential, tangential)) / lightSpeed
+	d := 1 + betaRadial
+	w := betaRadial*betaRadial + betaTangential*betaTangential
+	delta := -w / (math.Sqrt(1-w) + 1)
+	for axis := range velocity {
+		velocity[axis] = tangential[axis]/d + direction[axis]*lightSpeed*(betaRadial-delta)/d
+	}
+	return velocity
+}
+
+// getVelocityComponents раскладывает скорость velocity звезды в положении position на лучевую radial
+// вдоль направления direction и тангенциальную tangential.
+func getVelocityComponents(position, velocity [3]float64) (direction [3]float64, radial float64, tangential [3]float64) {
+	distance := math.Sqrt(getDotProduct(position, position))
+	for axis := range direction {
+		direction[axis] = position[axis] / distance
+	}
+	radial = getDotProduct(direction, velocity)
+	for axis := range tangential {
+		tangential[axis] = velocity[axis] - radial*direction[axis]
+	}
+	return direction, radial, tangential
+}
@@ -0,0 +1,102 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetMovedWithoutParallax(t *testing.T) {
+	tests := []struct {
+		name   string
+		motion SpaceMotion
+		years  float64
+		shift  float64 // изменение склонения в градусах
+	}{
+		{"2″/yr", SpaceMotion{ProperMotionDec: 2}, 26, 0.014444},
+		{"8″/yr", SpaceMotion{ProperMotionDec: 8}, 26, 0.057778},
+		{"1″/yr, 30 km/s", SpaceMotion{ProperMotionDec: 1, RadialVelocity: 30}, 100, 0.027778},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCoordsFromDegrees(10, 20)
+			moved, motion := c.GetMoved(test.motion, J2000, J2000+Epoch(test.years*julianYear))
+			if shift := moved.Latitude.Degrees() - c.Latitude.Degrees(); math.Abs(shift-test.shift) > 1e-6 {
+				t.Errorf("declination shift %.6f°, want %.6f°", shift, test.shift)
+			}
+			if math.Abs(moved.Longitude.Degrees()-10) > 1e-9 {
+				t.Errorf("right ascension %.9f°, want 10°", moved.Longitude.Degrees())
+			}
+			if motion.Parallax != 0 || motion.RadialVelocity != test.motion.RadialVelocity {
+				t.Errorf("motion %+v, want zero parallax and radial velocity %v", motion, test.motion.RadialVelocity)
+			}
+		})
+	}
+}
+
+// Эталоны вычислены функцией iauPmsafe библиотеки SOFA; первый — из её тестов t_sofa_c.
+func TestGetMovedSOFA(t *testing.T) {
+	tests := []struct {
+		name               string
+		ra, dec            float64 // радианы
+		pmRA, pmDec        float64 // dα/dt и dδ/dt, радианы в год
+		parallax, velocity float64
+		from, to           Epoch
+		wantRA, wantDec    float64
+		want               SpaceMotion // собственное движение как pmRA и pmDec
+	}{
+		{
+			"t_sofa_c", 1.234, 0.789, 1e-5, -2e-5, 1e-2, 10,
+			Epoch(2400000.5 + 48348.5625), Epoch(2400000.5 + 51544.5),
+			1.234087484501017061, 0.7888249982450468567,
+			SpaceMotion{0.9996457663586073988e-5, -0.2000040085106754565e-4, 0.9999997295356830666e-2, 10.38468380293920069},
+		},
+		{
+			"Barnard's Star", 269.45207511 * Degree, 4.69339088 * Degree,
+			-0.79858 * ArcSecond / math.Cos(4.69339088*Degree), 10.32812 * ArcSecond, 0.54831, -110.51,
+			J2000, J2000 + Epoch(26*julianYear),
+			269.446278221898751 * Degree, 4.768103191290377 * Degree,
+			SpaceMotion{-3.898036185426681e-06, 5.023379540549953e-05, 0.5491946738587442, -110.39273048923607},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			motion := SpaceMotion{
+				ProperMotionRA:  test.pmRA * math.Cos(test.dec) / ArcSecond,
+				ProperMotionDec: test.pmDec / ArcSecond,
+				Parallax:        test.parallax,
+				RadialVelocity:  test.velocity,
+			}
+			moved, result := NewSphericalCoords(test.ra, test.dec, 0).GetMoved(motion, test.from, test.to)
+			if math.Abs(moved.Longitude.Radians()-test.wantRA) > 1e-12 || math.Abs(moved.Latitude.Radians()-test.wantDec) > 1e-12 {
+				t.Errorf("coords %.15f, %.15f, want %.15f, %.15f",
+					moved.Longitude.Radians(), moved.Latitude.Radians(), test.wantRA, test.wantDec)
+			}
+			pmRA := result.ProperMotionRA * ArcSecond / moved.Latitude.Cos
+			pmDec := result.ProperMotionDec * ArcSecond
+			if math.Abs(pmRA-test.want.ProperMotionRA) > 1e-16 || math.Abs(pmDec-test.want.ProperMotionDec) > 1e-16 {
+				t.Errorf("proper motion %g, %g, want %g, %g", pmRA, pmDec, test.want.ProperMotionRA, test.want.ProperMotionDec)
+			}
+			if math.Abs(result.Parallax-test.want.Parallax) > 1e-12 ||
+				math.Abs(result.RadialVelocity-test.want.RadialVelocity) > 1e-9 {
+				t.Errorf("parallax %g, radial velocity %g, want %g, %g",
+					result.Parallax, result.RadialVelocity, test.want.Parallax, test.want.RadialVelocity)
+			}
+		})
+	}
+}
+
+func TestGetMovedRoundTrip(t *testing.T) {
+	c := NewCoordsFromDegrees(269.45207511, 4.69339088)
+	motion := SpaceMotion{ProperMotionRA: -0.79858, ProperMotionDec: 10.32812, Parallax: 0.54831, RadialVelocity: -110.51}
+	to := J2000 + Epoch(500*julianYear)
+	moved, movedMotion := c.GetMoved(motion, J2000, to)
+	back, backMotion := moved.GetMoved(movedMotion, to, J2000)
+	if math.Abs(back.Longitude.Radians()-c.Longitude.Radians()) > 1e-12 ||
+		math.Abs(back.Latitude.Radians()-c.Latitude.Radians()) > 1e-12 {
+		t.Errorf("round trip coords %v, want %v", back, c)
+	}
+	if math.Abs(backMotion.ProperMotionDec-motion.ProperMotionDec) > 1e-9 ||
+		math.Abs(backMotion.RadialVelocity-motion.RadialVelocity) > 1e-6 {
+		t.Errorf("round trip motion %+v, want %+v", backMotion, motion)
+	}
+}
@@ -0,0 +1,60 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+// Эталоны SOFA взяты из тестов t_sofa_c (iauNut00b, iauObl06), эталоны Meeus — из примера 22.a,
+// где использована модель IAU 1980: расхождение с IAU 2000B и IAU 2006 — до 0.04″.
+func TestGetNutation(t *testing.T) {
+	tests := []struct {
+		name                 string
+		epoch                Epoch
+		longitude, obliquity float64 // радианы
+		tolerance            float64
+	}{
+		{"SOFA iauNut00b", Epoch(2400000.5 + 53736.0), -0.9632552291148362783e-5, 0.4063197106621159367e-4, 1e-15},
+		{"Meeus 22.a", Epoch(2446895.5), -3.788 * ArcSecond, 9.443 * ArcSecond, 0.01 * ArcSecond},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nutation := GetNutation(test.epoch)
+			if math.Abs(nutation.Longitude-test.longitude) > test.tolerance ||
+				math.Abs(nutation.Obliquity-test.obliquity) > test.tolerance {
+				t.Errorf("nutation %g, %g, want %g, %g", nutation.Longitude, nutation.Obliquity, test.longitude, test.obliquity)
+			}
+		})
+	}
+}
+
+func TestGetObliquity(t *testing.T) {
+	tests := []struct {
+		name      string
+		obliquity func(Epoch) float64
+		epoch     Epoch
+		want      float64
+		tolerance float64
+	}{
+		{"SOFA iauObl06", GetMeanObliquity, Epoch(2400000.5 + 54388.0), 0.4090749229387258204, 1e-15},
+		{"Meeus 22.a mean", GetMeanObliquity, Epoch(2446895.5), NewAngleFromDegrees(23, 26, 27.407).Radians(), 0.05 * ArcSecond},
+		{"Meeus 22.a true", GetTrueObliquity, Epoch(2446895.5), NewAngleFromDegrees(23, 26, 36.850).Radians(), 0.05 * ArcSecond},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if obliquity := test.obliquity(test.epoch); math.Abs(obliquity-test.want) > test.tolerance {
+				t.Errorf("obliquity %.16f, want %.16f", obliquity, test.want)
+			}
+		})
+	}
+}
+
+func TestGetNutatedRoundTrip(t *testing.T) {
+	c := NewCoordsFromDegrees(41.05, 49.23)
+	epoch := Epoch(2462088.69)
+	back := getMeanFromTrue(c.GetNutated(epoch), epoch)
+	if math.Abs(back.Longitude.Radians()-c.Longitude.Radians()) > 1e-12 ||
+		math.Abs(back.Latitude.Radians()-c.Latitude.Radians()) > 1e-12 {
+		t.Errorf("round trip %v, want %v", back, c)
+	}
+}
@@ -0,0 +1,181 @@
+package gorewind
+
+import (
+	"math"
+	"sort"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// Positioned объект со сферическими координатами: *AstronomicalObject, *Location, *BSCRecord, *NGCRecord.
+type Positioned interface {
+	GetCoords() SphericalCoords
+}
+
+// PositionedObjects возвращает небесные объекты как []Positioned.
+func PositionedObjects(objects []*AstronomicalObject) []Positioned {
+	result := make([]Positioned, len(objects))
+	for i, object := range objects {
+		result[i] = object
+	}
+	return result
+}
+
+// PositionedLocations возвращает географические объекты как []Positioned.
+func PositionedLocations(locations []*Location) []Positioned {
+	result := make([]Positioned, len(locations))
+	for i, location := range locations {
+		result[i] = location
+	}
+	return result
+}
+
+// SortByDistance сортирует объекты по возрастанию углового расстояния до coords.
+// Объекты на одинаковом расстоянии сохраняют исходный порядок; объекты без координат оказываются в конце.
+func SortByDistance(items []Positioned, coords SphericalCoords) {
+	sorter := distanceSorter{items: items, distances: make([]float64, len(items))}
+	for i, item := range items {
+		if hasPosition(item) {
+			sorter.distances[i] = coords.GetDistance(item.GetCoords())
+		} else {
+			sorter.distances[i] = math.Inf(1)
+		}
+	}
+	sort.Stable(&sorter)
+}
+
+type distanceSorter struct {
+	items     []Positioned
+	distances []float64
+}
+
+func (s *distanceSorter) Len() int           { return len(s.items) }
+func (s *distanceSorter) Less(i, j int) bool { return s.distances[i] < s.distances[j] }
+
+func (s *distanceSorter) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.distances[i], s.distances[j] = s.distances[j], s.distances[i]
+}
+
+// FilterWithin возвращает объекты на угловом расстоянии не больше radius (в радианах) от coords в исходном порядке.
+// Объекты без координат пропускаются. Для многократного поиска среди одних и тех же объектов
+// быстрее SpatialIndex.Within.
+func FilterWithin(items []Positioned, coords SphericalCoords, radius float64) []Positioned {
+	var result []Positioned
+	for _, item := range items {
+		if hasPosition(item) && coords.IsOverlap(item.GetCoords(), radius) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Cluster объединяет объекты в группы, в которых каждый объект находится на угловом расстоянии не больше radius
+// (в радианах) хотя бы от одного другого объекта группы (метод «друзей друзей»).
+// Группы упорядочены по первому объекту группы в items, объекты в группе — по порядку в items.
+// Объекты без координат не входят ни в одну группу.
+func Cluster(items []Positioned, radius float64) [][]Positioned {
+	index := NewSpatialIndex()
+	for i, item := range items {
+		index.Add(clusterItem{Positioned: item, index: i})
+	}
+	index.Build()
+
+	// система непересекающихся множеств по индексам items
+	parents := make([]int, len(items))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+	for i, item := range items {
+		if !hasPosition(item) {
+			continue
+		}
+		for _, match := range index.Within(item.GetCoords(), radius) {
+			a, b := find(i), find(match.Item.(clusterItem).index)
+			if a < b {
+				parents[b] = a
+			} else if b < a {
+				parents[a] = b
+			}
+		}
+	}
+
+	var result [][]Positioned
+	groups := make(map[int]int)
+	for i, item := range items {
+		if !hasPosition(item) {
+			continue
+		}
+		root := find(i)
+		group, ok := groups[root]
+		if !ok {
+			group = len(result)
+			groups[root] = group
+			result = append(result, nil)
+		}
+		result[group] = append(result[group], item)
+	}
+	return result
+}
+
+// clusterItem объект с его индексом в исходном списке.
+type clusterItem struct {
+	Positioned
+	index int
+}
+
+// GroupBy группирует объекты по ключу key, сохраняя порядок объектов внутри группы.
+func GroupBy(items []Positioned, key func(Positioned) string) map[string][]Positioned {
+	result := make(map[string][]Positioned)
+	for _, item := range items {
+		k := key(item)
+		result[k] = append(result[k], item)
+	}
+	return result
+}
+
+// GroupByConstellation группирует небесные объекты по созвездиям.
+// Объекты без созвездия, в том числе географические, попадают в группу с пустым ключом.
+func GroupByConstellation(items []Positioned) map[string][]Positioned {
+	return GroupBy(items, func(item Positioned) string {
+		if object, ok := item.(interface{ GetConstellation() string }); ok {
+			return object.GetConstellation()
+		}
+		return ""
+	})
+}
+
+// GroupByCountry группирует географические объекты по кодам стран.
+// Объекты без кода страны, в том числе небесные, попадают в группу с пустым ключом.
+func GroupByCountry(items []Positioned) map[string][]Positioned {
+	return GroupBy(items, func(item Positioned) string {
+		if location, ok := item.(interface{ GetCountryCode() string }); ok {
+			return location.GetCountryCode()
+		}
+		return ""
+	})
+}
+
+// hasPosition проверяет, что координаты объекта заданы, см. hasCoords.
+func hasPosition(item Positioned) bool {
+	return hasCoords(item.GetCoords())
+}
+
+// hasCoords проверяет, что координаты заданы. Читатели каталогов оставляют пустые координаты нулевыми,
+// поэтому точка (0, 0), как и NaN, означает отсутствие координат.
+func hasCoords(c SphericalCoords) bool {
+	if math.IsNaN(c.Latitude.float64) || math.IsNaN(c.Longitude.float64) {
+		return false
+	}
+	return c.Latitude.float64 != 0 || c.Longitude.float64 != 0
+}
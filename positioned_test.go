@@ -0,0 +1,62 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClusterSkipsMissingCoords(t *testing.T) {
+	items := []Positioned{
+		&AstronomicalObject{Name: "Zero 1"},
+		&AstronomicalObject{Name: "Zero 2"},
+		&AstronomicalObject{Name: "NaN", Coords: NewSphericalCoords(math.NaN(), math.NaN(), 0)},
+		&AstronomicalObject{Name: "A", Coords: NewCoordsFromDegrees(10, 20)},
+		&AstronomicalObject{Name: "B", Coords: NewCoordsFromDegrees(10.01, 20)},
+	}
+	groups := Cluster(items, 0.1*Degree)
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Fatalf("groups: %v, want one group of A and B", groups)
+	}
+}
+
+func TestMissingCoordsSkipped(t *testing.T) {
+	zero := &AstronomicalObject{Name: "Zero"}
+	valid := &AstronomicalObject{Name: "Valid", Coords: NewCoordsFromDegrees(266.4, -28.9)}
+	items := []Positioned{zero, valid}
+
+	if result := FilterByGalacticLatitude(items, 0); len(result) != 1 || result[0] != valid {
+		t.Errorf("FilterByGalacticLatitude: %v, want only Valid", result)
+	}
+	healpix, err := NewHEALPix(4, HEALPixNested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bins := healpix.Bin(items)
+	if len(bins) != 1 || len(bins[healpix.GetObjectPixel(valid)]) != 1 {
+		t.Errorf("Bin: %v, want only Valid", bins)
+	}
+}
+
+func TestFilterWithinSkipsMissingCoords(t *testing.T) {
+	zero := &AstronomicalObject{Name: "Zero"}
+	near := &AstronomicalObject{Name: "Near", Coords: NewCoordsFromDegrees(0.5, 0)}
+	result := FilterWithin([]Positioned{zero, near}, NewCoordsFromDegrees(0.1, 0), Degree)
+	if len(result) != 1 || result[0] != near {
+		t.Errorf("FilterWithin: %v, want only Near", result)
+	}
+}
+
+func TestSortByDistanceMissingCoordsLast(t *testing.T) {
+	zero := &AstronomicalObject{Name: "Zero"}
+	nan := &AstronomicalObject{Name: "NaN", Coords: NewSphericalCoords(math.NaN(), math.NaN(), 0)}
+	near := &AstronomicalObject{Name: "Near", Coords: NewCoordsFromDegrees(10, 1)}
+	far := &AstronomicalObject{Name: "Far", Coords: NewCoordsFromDegrees(10, 5)}
+	items := []Positioned{zero, far, nan, near}
+	SortByDistance(items, NewCoordsFromDegrees(10, 0))
+	want := []Positioned{near, far, zero, nan}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Fatalf("order: %v, want Near, Far, Zero, NaN", items)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import "math"
+
+// Прецессия IAU 2006 (P03): N. Capitaine, P. T. Wallace, J. Chapront. Expressions for IAU 2000 precession quantities,
+// A&A 412, 567 (2003). Углы ζA, zA и θA задают поворот среднего экватора и равноденствия J2000
+// к среднему экватору и равноденствию эпохи: P = R3(−zA)·R2(θA)·R3(−ζA).
+
+// ArcSecond угловая секунда в радианах.
+const ArcSecond = Degree / 3600
+
+// GetPrecessed возвращает экваториальные координаты, отнесённые к среднему экватору и равноденствию эпохи to,
+// для координат, отнесённых к эпохе from. Собственное движение объектов не учитывается.
+// Координаты каталогов FK4 (B1950) перед прецессией нужно перевести в FK5: это не только прецессия.
+func (c SphericalCoords) GetPrecessed(from, to Epoch) SphericalCoords {
+	if from == to {
+		return c
+	}
+	coords := c
+	if from != J2000 {
+		zeta, z, theta := getPrecessionAngles(from)
+		coords = coords.GetOffset(-z-math.Pi/2, -theta, math.Pi/2-zeta)
+	}
+	if to != J2000 {
+		zeta, z, theta := getPrecessionAngles(to)
+		coords = coords.GetOffset(zeta-math.Pi/2, theta, z+math.Pi/2)
+	}
+	return coords
+}
+
+// getPrecessionAngles возвращает углы прецессии ζA, zA и θA (в радианах) от J2000 до эпохи epoch.
+func getPrecessionAngles(epoch Epoch) (zeta, z, theta float64) {
+	t := epoch.GetCenturies()
+	zeta = (2.650545 + (2306.083227+(0.2988499+(0.01801828+(-0.000005971-0.0000003173*t)*t)*t)*t)*t) * ArcSecond
+	z = (-2.650545 + (2306.077181+(1.0927348+(0.01826837+(-0.000028596-0.0000002904*t)*t)*t)*t)*t) * ArcSecond
+	theta = ((2004.191903 + (-0.4294934+(-0.04182264+(-0.000007089-0.0000001274*t)*t)*t)*t) * t) * ArcSecond
+	return zeta, z, theta
+}
@@ -0,0 +1,45 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+)
+
+// Столбцы матрицы iauPmat06 из тестов t_sofa_c — образы осей J2000 на эпоху. Матрица SOFA включает
+// смещение начала отсчёта ICRS (до 0.017″), которое GetPrecessed не учитывает.
+func TestGetPrecessedSOFA(t *testing.T) {
+	epoch := Epoch(2400000.5 + 50123.9999)
+	tests := []struct {
+		axis SphericalCoords
+		want [3]float64
+	}{
+		{NewCoordsFromDegrees(0, 0), [3]float64{0.9999995505176007047, -0.8695404723772031414e-3, -0.3779734957034089490e-3}},
+		{NewCoordsFromDegrees(90, 0), [3]float64{0.8695404617348208406e-3, 0.9999996219496027161, -0.1924880847894457113e-6}},
+		{NewCoordsFromDegrees(0, 90), [3]float64{0.3779735201865589104e-3, -0.1361752497080270143e-6, 0.9999999285679971958}},
+	}
+	for _, test := range tests {
+		point := getUnitVector(test.axis.GetPrecessed(J2000, epoch))
+		for axis := range point {
+			if math.Abs(point[axis]-test.want[axis]) > 1e-7 {
+				t.Errorf("%v: %v, want %v", test.axis, point, test.want)
+				break
+			}
+		}
+	}
+}
+
+// Пример 21.b Meeus (θ Persei) вычислен с прецессией IAU 1976: за 28 лет она расходится с IAU 2006 на 0.06″.
+func TestGetPrecessedMeeus(t *testing.T) {
+	c := NewClockCoords(2, 44, 12.975, 49, 13, 39.90)
+	want := NewClockCoords(2, 46, 11.331, 49, 20, 54.54)
+	precessed := c.GetPrecessed(J2000, Epoch(2462088.69))
+	if distance := precessed.GetDistance(want); distance > 0.1*ArcSecond {
+		t.Errorf("precessed %v, want %v (%.3f″)", precessed, want, distance/ArcSecond)
+	}
+
+	back := precessed.GetPrecessed(Epoch(2462088.69), J2000)
+	if math.Abs(back.Longitude.Radians()-c.Longitude.Radians()) > 1e-12 ||
+		math.Abs(back.Latitude.Radians()-c.Latitude.Radians()) > 1e-12 {
+		t.Errorf("round trip %v, want %v", back, c)
+	}
+}
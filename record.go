@@ -3,6 +3,7 @@ package gorewind
 import (
 	"strconv"
 	"strings"
+	"time"
 )
 
 // gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
@@ -20,12 +21,18 @@ type AstronomicalObject struct {
 	AlternateNames []string
 	Magnitude      float64
 	Coords         SphericalCoords
+	Motion         SpaceMotion // пространственное движение, см. GetCoordsAt
 }
 
 func (ao *AstronomicalObject) GetCoords() SphericalCoords {
 	return ao.Coords
 }
 
+// GetConstellation возвращает сокращённое название созвездия, например, And.
+func (ao *AstronomicalObject) GetConstellation() string {
+	return ao.Designation.Constellation
+}
+
 func (ao *AstronomicalObject) GetRecord() []string {
 	s := []string{
 		ao.Name,
@@ -62,14 +69,34 @@ func (ao *AstronomicalObject) GetRecord() []string {
 	return s
 }
 
+// Location географический объект GeoNames.
 type Location struct {
-	Name        string
-	LocalName   string
-	Population  uint
-	CountryCode string
-	Coords      SphericalCoords
+	GeoNameID             uint     // идентификатор GeoNames
+	Name                  string   // название в UTF-8
+	ASCIIName             string   // название в ASCII
+	LocalName             string   // название на языке CitiesReader.Language, по умолчанию на русском
+	AlternateNames        []string // альтернативные названия
+	FeatureClass          string   // класс объекта, например, P (населённые пункты)
+	FeatureCode           string   // код объекта, например, PPLA (административный центр первого уровня)
+	Population            uint
+	CountryCode           string   // код страны ISO-3166
+	AlternateCountryCodes []string // альтернативные коды стран
+	Admin1Code            string   // код административного деления первого уровня (admin1CodesASCII.txt)
+	Admin2Code            string   // код административного деления второго уровня (admin2Codes.txt)
+	Admin3Code            string   // код административного деления третьего уровня
+	Admin4Code            string   // код административного деления четвёртого уровня
+	Elevation             int      // высота над уровнем моря в метрах, 0 если неизвестна
+	DEM                   int      // средняя высота местности по цифровой модели рельефа (SRTM3 или GTOPO30) в метрах
+	TimeZone              string   // идентификатор часового пояса IANA, например, Europe/Moscow
+	ModificationDate      time.Time
+	Coords                SphericalCoords
 }
 
 func (l *Location) GetCoords() SphericalCoords {
 	return l.Coords
 }
+
+// GetCountryCode возвращает код страны ISO-3166.
+func (l *Location) GetCountryCode() string {
+	return l.CountryCode
+}
@@ -0,0 +1,78 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// StandardHorizon высота центра звезды (в радианах) в момент видимого восхода и захода
+	// при стандартной рефракции 34′ у горизонта.
+	StandardHorizon = -34.0 / 60 * Degree
+	// siderealRate количество оборотов Земли относительно звёзд за солнечные сутки.
+	siderealRate = 1.00273781191135448
+)
+
+// RiseSet время восхода, верхней кульминации и захода объекта.
+// У незаходящих (Circumpolar) и невосходящих (NeverRises) объектов время восхода и захода нулевое.
+type RiseSet struct {
+	Rise            time.Time
+	Transit         time.Time
+	Set             time.Time
+	TransitAltitude Angle // высота в верхней кульминации без учёта рефракции
+	Circumpolar     bool
+	NeverRises      bool
+}
+
+// GetRiseSet возвращает время восхода, кульминации и захода объекта в месте l в сутки date
+// (от полуночи в часовом поясе date.Location()); horizon — высота горизонта в радианах, обычно StandardHorizon.
+// Звёздные сутки короче солнечных на 4 минуты, поэтому второй восход, кульминация или заход
+// в конце тех же суток не возвращается. Время возвращается в часовом поясе date.
+func (ao *AstronomicalObject) GetRiseSet(l *Location, date time.Time, horizon float64) RiseSet {
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+	// за сутки видимое место звезды смещается меньше чем на секунду дуги
+	coords := ao.GetApparentCoords(NewEpoch(start.Add(end.Sub(start) / 2)))
+
+	hourAngle := l.GetApparentSiderealTime(start).float64 - coords.Longitude.float64
+	var result RiseSet
+	result.Transit = start.Add(getSiderealDuration(normalizeLongitude(-hourAngle)))
+	result.TransitAltitude = l.GetHorizontal(coords, result.Transit).Latitude
+
+	latitude := l.Coords.Latitude
+	cos := (math.Sin(horizon) - latitude.Sin*coords.Latitude.Sin) / (latitude.Cos * coords.Latitude.Cos)
+	switch {
+	case cos < -1:
+		result.Circumpolar = true
+	case cos > 1 || math.IsNaN(cos):
+		result.NeverRises = true
+	default:
+		semiArc := getSiderealDuration(math.Acos(cos))
+		result.Rise = getTimeInDay(result.Transit.Add(-semiArc), start, end)
+		result.Set = getTimeInDay(result.Transit.Add(semiArc), start, end)
+	}
+	return result
+}
+
+// getSiderealDuration возвращает время, за которое звёздное время изменяется на угол angle (в радианах).
+func getSiderealDuration(angle float64) time.Duration {
+	return time.Duration(angle / (2 * math.Pi) / siderealRate * float64(24*time.Hour))
+}
+
+// getTimeInDay сдвигает момент t на целое число звёздных суток в интервал от start до end.
+func getTimeInDay(t, start, end time.Time) time.Time {
+	day := getSiderealDuration(2 * math.Pi)
+	for t.Before(start) {
+		t = t.Add(day)
+	}
+	for !t.Before(end) {
+		t = t.Add(-day)
+	}
+	return t
+}
@@ -0,0 +1,65 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGetRiseSet(t *testing.T) {
+	moscow := &Location{Coords: NewCoordsFromDegrees(37.6173, 55.7558)}
+	date := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		coords      SphericalCoords
+		circumpolar bool
+		neverRises  bool
+	}{
+		{"Sirius", NewClockCoords(6, 45, 8.917, -16, 42, 58.02), false, false},
+		{"Polaris", NewClockCoords(2, 31, 49.09, 89, 15, 50.8), true, false},
+		{"Canopus", NewClockCoords(6, 23, 57.11, -52, 41, 44.4), false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			star := &AstronomicalObject{Coords: test.coords}
+			result := star.GetRiseSet(moscow, date, StandardHorizon)
+			if result.Circumpolar != test.circumpolar || result.NeverRises != test.neverRises {
+				t.Fatalf("circumpolar %v, never rises %v", result.Circumpolar, result.NeverRises)
+			}
+			if result.Transit.Before(date) || !result.Transit.Before(date.AddDate(0, 0, 1)) {
+				t.Errorf("transit %v outside the day", result.Transit)
+			}
+
+			// в кульминации звезда на юге на высоте 90° − φ + δ
+			transit := star.GetHorizontalCoords(moscow, result.Transit, nil)
+			apparent := star.GetApparentCoords(NewEpoch(result.Transit))
+			want := 90 - moscow.Coords.Latitude.Degrees() + apparent.Latitude.Degrees()
+			if want > 90 {
+				// кульминация к северу от зенита
+				want = 180 - want
+			}
+			if math.Abs(transit.Latitude.Degrees()-want) > 0.01 || math.Abs(result.TransitAltitude.Degrees()-want) > 0.01 {
+				t.Errorf("transit altitude %.4f°, %.4f°, want %.4f°", transit.Latitude.Degrees(), result.TransitAltitude.Degrees(), want)
+			}
+
+			if test.circumpolar || test.neverRises {
+				if !result.Rise.IsZero() || !result.Set.IsZero() {
+					t.Errorf("rise %v, set %v, want zero", result.Rise, result.Set)
+				}
+				return
+			}
+			for _, moment := range []time.Time{result.Rise, result.Set} {
+				if moment.Before(date) || !moment.Before(date.AddDate(0, 0, 1)) {
+					t.Errorf("%v outside the day", moment)
+				}
+				altitude := star.GetHorizontalCoords(moscow, moment, nil).Latitude.Radians()
+				if math.Abs(altitude-StandardHorizon) > 0.01*Degree {
+					t.Errorf("altitude at %v: %.4f°, want %.4f°", moment, altitude/Degree, StandardHorizon/Degree)
+				}
+			}
+			if azimuth := star.GetHorizontalCoords(moscow, result.Rise, nil).Longitude.Degrees(); azimuth > 180 {
+				t.Errorf("rise azimuth %.2f°, want east", azimuth)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import (
+	"math"
+	"time"
+)
+
+// Звёздное время IAU 2006: угол поворота Земли (ERA) по UT1 и полином по TT, как в функции iauGmst06 библиотеки SOFA.
+// Уравнение равноденствий — нутация в долготе, спроецированная на экватор, с двумя главными дополнительными членами.
+// Все углы возвращаются в диапазоне от 0 до 24 часов, часы и минуты — методом Angle.Clock.
+
+// GetGreenwichMeanSiderealTime возвращает среднее гринвичское звёздное время в момент t.
+func GetGreenwichMeanSiderealTime(t time.Time) Angle {
+	return newAngle(getMeanSiderealTime(NewEpoch(t)))
+}
+
+// GetGreenwichApparentSiderealTime возвращает истинное гринвичское звёздное время в момент t.
+func GetGreenwichApparentSiderealTime(t time.Time) Angle {
+	return newAngle(getApparentSiderealTime(NewEpoch(t)))
+}
+
+// GetMeanSiderealTime возвращает среднее местное звёздное время в месте l в момент t.
+func (l *Location) GetMeanSiderealTime(t time.Time) Angle {
+	return newAngle(normalizeLongitude(getMeanSiderealTime(NewEpoch(t)) + l.Coords.Longitude.float64))
+}
+
+// GetApparentSiderealTime возвращает истинное местное звёздное время в месте l в момент t.
+func (l *Location) GetApparentSiderealTime(t time.Time) Angle {
+	return newAngle(normalizeLongitude(getApparentSiderealTime(NewEpoch(t)) + l.Coords.Longitude.float64))
+}
+
+// GetHourAngle возвращает часовой угол объекта в месте l в момент t — разность истинного местного звёздного времени
+// и прямого восхождения видимого места объекта (см. GetApparentCoords), от 0 до 24 часов к западу от меридиана.
+func (ao *AstronomicalObject) GetHourAngle(l *Location, t time.Time) Angle {
+	epoch := NewEpoch(t)
+	coords := ao.GetApparentCoords(epoch)
+	return newAngle(normalizeLongitude(getApparentSiderealTime(epoch) + l.Coords.Longitude.float64 - coords.Longitude.float64))
+}
+
+// getEarthRotationAngle возвращает угол поворота Земли (в радианах) для эпохи epoch.
+func getEarthRotationAngle(epoch Epoch) float64 {
+	days := epoch.JulianDateIn(UT1) - float64(J2000)
+	// дробная часть суток отделяется до умножения, чтобы не терять точность
+	turns := math.Mod(days, 1) + 0.7790572732640 + 0.00273781191135448*days
+	return normalizeLongitude(2 * math.Pi * turns)
+}
+
+// getMeanSiderealTime возвращает среднее гринвичское звёздное время (в радианах) для эпохи epoch.
+func getMeanSiderealTime(epoch Epoch) float64 {
+	t := epoch.GetCenturies()
+	polynomial := (0.014506 + (4612.156534+(1.3915817+(-0.00000044+(-0.000029956-0.0000000368*t)*t)*t)*t)*t) * ArcSecond
+	return normalizeLongitude(getEarthRotationAngle(epoch) + polynomial)
+}
+
+// getApparentSiderealTime возвращает истинное гринвичское звёздное время (в радианах) для эпохи epoch.
+func getApparentSiderealTime(epoch Epoch) float64 {
+	return normalizeLongitude(getMeanSiderealTime(epoch) + getEquationOfEquinoxes(epoch))
+}
+
+// getEquationOfEquinoxes возвращает уравнение равноденствий (в радианах) для эпохи epoch.
+func getEquationOfEquinoxes(epoch Epoch) float64 {
+	t := epoch.GetCenturies()
+	om := math.Mod(450160.398036-6962890.5431*t, turnArcSeconds) * ArcSecond // долгота восходящего узла Луны
+	complementary := (0.00264096*math.Sin(om) + 0.00006352*math.Sin(2*om)) * ArcSecond
+	return GetNutation(epoch).Longitude*math.Cos(GetMeanObliquity(epoch)) + complementary
+}
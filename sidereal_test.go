@@ -0,0 +1,35 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Эталоны — примеры 12.a, 12.b и 13.b Meeus; всемирное время UT1 принято равным UTC.
+func TestGetSiderealTime(t *testing.T) {
+	washington := &Location{Coords: NewSphericalCoords(-NewAngleFromDegrees(77, 3, 56).Radians(), NewAngleFromDegrees(38, 55, 17).Radians(), 0)}
+	tests := []struct {
+		name     string
+		sidereal func(time.Time) Angle
+		time     time.Time
+		want     Angle
+	}{
+		{"Meeus 12.a mean", GetGreenwichMeanSiderealTime, time.Date(1987, 4, 10, 0, 0, 0, 0, time.UTC), NewClockAngle(13, 10, 46.3668)},
+		{"Meeus 12.a apparent", GetGreenwichApparentSiderealTime, time.Date(1987, 4, 10, 0, 0, 0, 0, time.UTC), NewClockAngle(13, 10, 46.1351)},
+		{"Meeus 12.b mean", GetGreenwichMeanSiderealTime, time.Date(1987, 4, 10, 19, 21, 0, 0, time.UTC), NewClockAngle(8, 34, 57.0896)},
+		// θ0 − L из примера 13.b: 8h34m56.853s − 77°03′56″
+		{"Meeus 13.b local apparent", washington.GetApparentSiderealTime, time.Date(1987, 4, 10, 19, 21, 0, 0, time.UTC),
+			newAngle(NewClockAngle(8, 34, 56.853).Radians() - NewAngleFromDegrees(77, 3, 56).Radians())},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.sidereal(test.time)
+			difference := math.Remainder(got.Radians()-test.want.Radians(), 2*math.Pi)
+			// 0.01 с времени
+			if seconds := difference / (15 * ArcSecond); math.Abs(seconds) > 0.01 {
+				t.Errorf("%.6fh, want %.6fh (%+.4f s)", got.Hours(), test.want.Hours(), seconds)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package gorewind
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+// Пространственный индекс — k-d дерево по единичным векторам направлений.
+// В отличие от дерева по долготе и широте, расстояния между векторами не искажаются у полюсов и на нулевом меридиане.
+
+// SpatialIndex индекс объектов Positioned, например, *AstronomicalObject или *Location,
+// для поиска ближайших объектов, объектов в конусе и в прямоугольной области.
+// Дерево строится при первом поиске после Add. Add нельзя вызывать одновременно с поиском;
+// для поиска из нескольких горутин дерево нужно предварительно построить методом Build.
+type SpatialIndex struct {
+	items []spatialItem
+	nodes []spatialNode
+	root  int
+	built bool
+}
+
+// SpatialMatch объект, найденный в индексе, и его угловое расстояние до точки поиска (в радианах).
+type SpatialMatch struct {
+	Item     Positioned
+	Distance float64
+}
+
+// SphericalBox прямоугольная область в сферических координатах (в радианах).
+// Если MinLongitude больше MaxLongitude, область пересекает нулевой меридиан.
+type SphericalBox struct {
+	MinLongitude, MaxLongitude float64
+	MinLatitude, MaxLatitude   float64
+}
+
+type spatialItem struct {
+	value Positioned
+	point [3]float64
+}
+
+type spatialNode struct {
+	item        int // индекс в SpatialIndex.items
+	left, right int // индексы потомков в SpatialIndex.nodes, -1 если потомка нет
+	min, max    [3]float64
+}
+
+// NewSpatialIndex создаёт пустой индекс.
+func NewSpatialIndex() *SpatialIndex {
+	return &SpatialIndex{root: -1}
+}
+
+// Add добавляет объекты в индекс. Объекты без координат (нулевые или NaN, см. hasCoords) не добавляются.
+func (index *SpatialIndex) Add(items ...Positioned) {
+	for _, item := range items {
+		if !hasPosition(item) {
+			continue
+		}
+		index.items = append(index.items, spatialItem{value: item, point: getUnitVector(item.GetCoords())})
+		index.built = false
+	}
+}
+
+// Len возвращает количество объектов в индексе.
+func (index *SpatialIndex) Len() int {
+	return len(index.items)
+}
+
+// Build строит дерево. Вызывается автоматически при первом поиске после Add.
+func (index *SpatialIndex) Build() {
+	if index.built {
+		return
+	}
+	indices := make([]int, len(index.items))
+	for i := range indices {
+		indices[i] = i
+	}
+	index.nodes = make([]spatialNode, 0, len(index.items))
+	index.root = index.build(indices)
+	index.built = true
+}
+
+func (index *SpatialIndex) build(indices []int) int {
+	if len(indices) == 0 {
+		return -1
+	}
+
+	// разделение по оси с наибольшим разбросом
+	var min, max [3]float64
+	min = index.items[indices[0]].point
+	max = min
+	for _, i := range indices[1:] {
+		for axis, value := range index.items[i].point {
+			min[axis] = math.Min(min[axis], value)
+			max[axis] = math.Max(max[axis], value)
+		}
+	}
+	axis := 0
+	for a := 1; a < 3; a++ {
+		if max[a]-min[a] > max[axis]-min[axis] {
+			axis = a
+		}
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return index.items[indices[i]].point[axis] < index.items[indices[j]].point[axis]
+	})
+
+	median := len(indices) / 2
+	node := len(index.nodes)
+	index.nodes = append(index.nodes, spatialNode{item: indices[median], min: min, max: max})
+	left := index.build(indices[:median])
+	right := index.build(indices[median+1:])
+	index.nodes[node].left = left
+	index.nodes[node].right = right
+	return node
+}
+
+// Nearest возвращает k ближайших к coords объектов в порядке возрастания расстояния.
+func (index *SpatialIndex) Nearest(coords SphericalCoords, k int) []SpatialMatch {
+	index.Build()
+	if k <= 0 || index.root < 0 {
+		return nil
+	}
+	point := getUnitVector(coords)
+	nearest := make(spatialHeap, 0, k)
+	index.nearest(index.root, point, k, &nearest)
+
+	result := make([]SpatialMatch, len(nearest))
+	for i := len(nearest) - 1; i >= 0; i-- {
+		item := heap.Pop(&nearest).(spatialCandidate)
+		result[i] = index.getMatch(item.item, item.chord)
+	}
+	return result
+}
+
+func (index *SpatialIndex) nearest(node int, point [3]float64, k int, nearest *spatialHeap) {
+	if node < 0 {
+		return
+	}
+	n := &index.nodes[node]
+	if len(*nearest) == k && getBoxDistance(point, n.min, n.max) > (*nearest)[0].chord {
+		return
+	}
+
+	chord := getChordSquare(point, index.items[n.item].point)
+	if len(*nearest) < k {
+		heap.Push(nearest, spatialCandidate{item: n.item, chord: chord})
+	} else if chord < (*nearest)[0].chord {
+		(*nearest)[0] = spatialCandidate{item: n.item, chord: chord}
+		heap.Fix(nearest, 0)
+	}
+
+	// сначала обходится потомок, ближайший к точке поиска
+	first, second := n.left, n.right
+	if second >= 0 && (first < 0 ||
+		getBoxDistance(point, index.nodes[second].min, index.nodes[second].max) <
+			getBoxDistance(point, index.nodes[first].min, index.nodes[first].max)) {
+		first, second = second, first
+	}
+	index.nearest(first, point, k, nearest)
+	index.nearest(second, point, k, nearest)
+}
+
+// Within возвращает объекты на угловом расстоянии не больше radius (в радианах) от coords
+// в порядке возрастания расстояния (поиск в конусе).
+func (index *SpatialIndex) Within(coords SphericalCoords, radius float64) []SpatialMatch {
+	index.Build()
+	if radius < 0 {
+		return nil
+	}
+	chord := 2 * math.Sin(math.Min(radius, math.Pi)/2)
+	point := getUnitVector(coords)
+
+	var result []SpatialMatch
+	index.within(index.root, point, chord*chord, func(item int, chord float64) {
+		match := index.getMatch(item, chord)
+		if match.Distance <= radius {
+			result = append(result, match)
+		}
+	})
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Distance < result[j].Distance
+	})
+	return result
+}
+
+func (index *SpatialIndex) within(node int, point [3]float64, maxChord float64, fn func(item int, chord float64)) {
+	if node < 0 {
+		return
+	}
+	n := &index.nodes[node]
+	// запас на ошибки округления: точное сравнение выполняется по угловому расстоянию
+	if getBoxDistance(point, n.min, n.max) > maxChord*(1+1e-12)+1e-15 {
+		return
+	}
+	if chord := getChordSquare(point, index.items[n.item].point); chord <= maxChord*(1+1e-12)+1e-15 {
+		fn(n.item, chord)
+	}
+	index.within(n.left, point, maxChord, fn)
+	index.within(n.right, point, maxChord, fn)
+}
+
+// InBox возвращает объекты в прямоугольной области box.
+func (index *SpatialIndex) InBox(box SphericalBox) []Positioned {
+	index.Build()
+	var result []Positioned
+	index.inBox(index.root, &box, func(item int) {
+		result = append(result, index.items[item].value)
+	})
+	return result
+}
+
+func (index *SpatialIndex) inBox(node int, box *SphericalBox, fn func(item int)) {
+	if node < 0 {
+		return
+	}
+	n := &index.nodes[node]
+	if !box.intersects(n.min, n.max) {
+		return
+	}
+	if box.Contains(index.items[n.item].value.GetCoords()) {
+		fn(n.item)
+	}
+	index.inBox(n.left, box, fn)
+	index.inBox(n.right, box, fn)
+}
+
+func (index *SpatialIndex) getMatch(item int, chord float64) SpatialMatch {
+	return SpatialMatch{
+		Item:     index.items[item].value,
+		Distance: 2 * math.Asin(math.Min(math.Sqrt(chord)/2, 1)),
+	}
+}
+
+// Contains проверяет, что координаты находятся в области.
+func (box *SphericalBox) Contains(coords SphericalCoords) bool {
+	latitude := coords.Latitude.Radians()
+	if latitude < box.MinLatitude || latitude > box.MaxLatitude {
+		return false
+	}
+	longitude := normalizeLongitude(coords.Longitude.Radians())
+	minLongitude, maxLongitude := normalizeLongitude(box.MinLongitude), normalizeLongitude(box.MaxLongitude)
+	if minLongitude <= maxLongitude {
+		return longitude >= minLongitude && longitude <= maxLongitude
+	}
+	return longitude >= minLongitude || longitude <= maxLongitude
+}
+
+// intersects проверяет, может ли параллелепипед [min, max] содержать единичные векторы из области.
+// Широта ограничивает z, долгота — полуплоскостями через ось полюсов.
+func (box *SphericalBox) intersects(min, max [3]float64) bool {
+	if min[2] > math.Sin(box.MaxLatitude) || max[2] < math.Sin(box.MinLatitude) {
+		return false
+	}
+	minLongitude, maxLongitude := normalizeLongitude(box.MinLongitude), normalizeLongitude(box.MaxLongitude)
+	width := maxLongitude - minLongitude
+	if width < 0 {
+		width += 2 * math.Pi
+	}
+	if width >= math.Pi {
+		return true
+	}
+	// точки области лежат по левую сторону от меридиана minLongitude и по правую — от maxLongitude
+	sinMin, cosMin := math.Sincos(minLongitude)
+	sinMax, cosMax := math.Sincos(maxLongitude)
+	return getHalfSpaceMax([3]float64{-sinMin, cosMin, 0}, min, max) >= -1e-15 &&
+		getHalfSpaceMax([3]float64{sinMax, -cosMax, 0}, min, max) >= -1e-15
+}
+
+// getHalfSpaceMax возвращает максимум скалярного произведения normal на точки параллелепипеда [min, max].
+func getHalfSpaceMax(normal, min, max [3]float64) float64 {
+	var sum float64
+	for axis := range normal {
+		if normal[axis] >= 0 {
+			sum += normal[axis] * max[axis]
+		} else {
+			sum += normal[axis] * min[axis]
+		}
+	}
+	return sum
+}
+
+func normalizeLongitude(longitude float64) float64 {
+	longitude = math.Mod(longitude, 2*math.Pi)
+	if longitude < 0 {
+		longitude += 2 * math.Pi
+	}
+	return longitude
+}
+
+// getUnitVector возвращает единичный вектор направления на точку с координатами coords.
+func getUnitVector(coords SphericalCoords) [3]float64 {
+	return [3]float64{
+		coords.Latitude.Cos * coords.Longitude.Cos,
+		coords.Latitude.Cos * coords.Longitude.Sin,
+		coords.Latitude.Sin,
+	}
+}
+
+// getVectorCoords возвращает сферические координаты с радиусом radius для направления point (вектор любой длины).
+func getVectorCoords(point [3]float64, radius float64) SphericalCoords {
+	length := math.Sqrt(getDotProduct(point, point))
+	latitude := math.Asin(math.Max(-1, math.Min(1, point[2]/length)))
+	return NewSphericalCoords(normalizeLongitude(math.Atan2(point[1], point[0])), latitude, radius)
+}
+
+// getChordSquare возвращает квадрат расстояния между точками.
+func getChordSquare(a, b [3]float64) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dx*dx + dy*dy + dz*dz
+}
+
+// getBoxDistance возвращает квадрат расстояния от точки до параллелепипеда [min, max].
+func getBoxDistance(point, min, max [3]float64) float64 {
+	var sum float64
+	for axis, value := range point {
+		if value < min[axis] {
+			sum += (min[axis] - value) * (min[axis] - value)
+		} else if value > max[axis] {
+			sum += (value - max[axis]) * (value - max[axis])
+		}
+	}
+	return sum
+}
+
+type spatialCandidate struct {
+	item  int
+	chord float64
+}
+
+// spatialHeap куча кандидатов, на вершине которой самый дальний.
+type spatialHeap []spatialCandidate
+
+func (h spatialHeap) Len() int            { return len(h) }
+func (h spatialHeap) Less(i, j int) bool  { return h[i].chord > h[j].chord }
+func (h spatialHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *spatialHeap) Push(x interface{}) { *h = append(*h, x.(spatialCandidate)) }
+
+func (h *spatialHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,25 @@
+package gorewind
+
+import "testing"
+
+func TestSpatialIndexSkipsMissingCoords(t *testing.T) {
+	zero := &AstronomicalObject{Name: "Zero"}
+	near := &AstronomicalObject{Name: "Near", Coords: NewCoordsFromDegrees(0.01, 0.01)}
+	index := NewSpatialIndex()
+	index.Add(zero, near)
+
+	if index.Len() != 1 {
+		t.Fatalf("Len: %d, want 1", index.Len())
+	}
+	origin := NewCoordsFromDegrees(0, 0)
+	if matches := index.Nearest(origin, 2); len(matches) != 1 || matches[0].Item != near {
+		t.Errorf("Nearest: %v, want only Near", matches)
+	}
+	if matches := index.Within(origin, Degree); len(matches) != 1 || matches[0].Item != near {
+		t.Errorf("Within: %v, want only Near", matches)
+	}
+	box := SphericalBox{MinLongitude: -Degree, MaxLongitude: Degree, MinLatitude: -Degree, MaxLatitude: Degree}
+	if items := index.InBox(box); len(items) != 1 || items[0] != near {
+		t.Errorf("InBox: %v, want only Near", items)
+	}
+}
@@ -24,9 +24,14 @@ func NewCoordsFromDegrees(longitude, latitude float64) SphericalCoords {
 }
 
 // NewClockCoords создаёт новые сферические координаты, заданные через часы, минуты и секунды.
+// Знак отрицательной широты относится и к минутам, и к секундам: -16°42' это -16.7°.
+// Широта от -1° до 0° задаётся отрицательными минутами и секундами: (0, -17, -57) это -0°17′57″.
 func NewClockCoords(longitudeHours uint, longitudeMinutes, longitudeSeconds float64, latitudeDegrees int, latitudeMinutes, latitudeSeconds float64) SphericalCoords {
 	longitude := 15 * (float64(longitudeHours) + longitudeMinutes/60 + longitudeSeconds/3600)
-	latitude := float64(latitudeDegrees) + latitudeMinutes/60 + latitudeSeconds/3600
+	latitude := math.Abs(float64(latitudeDegrees)) + latitudeMinutes/60 + latitudeSeconds/3600
+	if latitudeDegrees < 0 {
+		latitude = -latitude
+	}
 	return NewCoordsFromDegrees(longitude, latitude)
 }
 
@@ -41,11 +46,22 @@ func NewSphericalCoords(longitude, latitude, radius float64) SphericalCoords {
 
 // IsOverlap проверяет пересечение двух точек, заданных через сферические координаты, с погрешностью threshold (в радианах).
 func (c *SphericalCoords) IsOverlap(coords SphericalCoords, threshold float64) bool {
+	return c.GetDistance(coords) <= threshold
+}
+
+// GetDistance возвращает угловое расстояние (в радианах) между двумя точками, заданными через сферические координаты.
+func (c *SphericalCoords) GetDistance(coords SphericalCoords) float64 {
 	// delta is cos of angle between c and coords
 	delta := c.Latitude.Sin*coords.Latitude.Sin +
 		c.Latitude.Cos*coords.Latitude.Cos*
 			(c.Longitude.Cos*coords.Longitude.Cos+c.Longitude.Sin*coords.Longitude.Sin)
-	return math.Acos(delta) <= threshold
+	// rounding errors can move delta out of the acos domain for identical points
+	if delta > 1 {
+		delta = 1
+	} else if delta < -1 {
+		delta = -1
+	}
+	return math.Acos(delta)
 }
 
 // getRotated смещеает сферические координаты на три угла Эйлера:
@@ -60,9 +76,9 @@ func (c *SphericalCoords) GetOffset(rotation, precession, nutation float64) Sphe
 
 // GetRotated возвращает сферические координаты, вращённые относительно полюсов.
 func (c SphericalCoords) GetRotated(offset float64) SphericalCoords {
-	longitude := c.Longitude.float64 + offset
-	if longitude >= math.Pi*2 {
-		longitude -= math.Pi * 2
+	longitude := math.Mod(c.Longitude.float64+offset, math.Pi*2)
+	if longitude < 0 {
+		longitude += math.Pi * 2
 	}
 	return SphericalCoords{
 		Longitude: newAngle(longitude),
@@ -73,46 +89,38 @@ func (c SphericalCoords) GetRotated(offset float64) SphericalCoords {
 
 // GetOriented возвращает координаты в новой системе координат, смещённой относительно нулевого меридиана.
 func (c SphericalCoords) GetOriented(offset float64) SphericalCoords {
-	const (
-		degrees90  = math.Pi / 2
-		degrees270 = math.Pi * 1.5
-	)
-
-	if c.Latitude.float64 == degrees90 { // is north pole
-		return NewSphericalCoords(degrees90, degrees90-offset, c.Radius)
-	} else if c.Latitude.float64 == -degrees90 { // is south pole
-		return NewSphericalCoords(degrees270, -degrees90+offset, c.Radius)
-	}
-
 	latitudeOffsetCos := math.Cos(offset)
 	latitudeOffsetSin := math.Sin(offset)
-	newLatitude := math.Asin(c.Latitude.Sin*latitudeOffsetCos - c.Latitude.Cos*latitudeOffsetSin*c.Longitude.Sin)
-
-	if c.Longitude.float64 == degrees90 || c.Longitude.float64 == degrees270 {
-		return SphericalCoords{
-			Longitude: c.Longitude,
-			Latitude:  newAngle(newLatitude),
-		}
-	}
+	sin := c.Latitude.Sin*latitudeOffsetCos - c.Latitude.Cos*latitudeOffsetSin*c.Longitude.Sin
+	// rounding errors can move sin out of the asin domain near the poles
+	newLatitude := math.Asin(math.Max(-1, math.Min(1, sin)))
 
 	x := c.Latitude.Cos * c.Longitude.Cos
 	y := c.Latitude.Sin*latitudeOffsetSin + c.Latitude.Cos*latitudeOffsetCos*c.Longitude.Sin
 
-	newLongitude := math.Atan(y / x)
-	if x < 0 {
-		newLongitude += math.Pi
-	} else if y < 0 {
+	newLongitude := math.Atan2(y, x)
+	if newLongitude < 0 {
 		newLongitude += 2 * math.Pi
+		if newLongitude >= 2*math.Pi { // -0 and tiny negative angles
+			newLongitude = 0
+		}
 	}
 
 	return NewSphericalCoords(newLongitude, newLatitude, c.Radius)
 }
 
-// GetEcliptic возвращает эклиптические координаты.
-func (c SphericalCoords) GetEcliptic() SphericalCoords {
-	// TODO correction by time
-	const eclipticOffset = (23 + 26/60 + 21.406/3600) * Degree
-	return c.GetOriented(eclipticOffset)
+// GetEcliptic возвращает эклиптические координаты, отнесённые к средней эклиптике и равноденствию эпохи epoch,
+// для экваториальных координат, отнесённых к среднему экватору той же эпохи.
+// Для видимых координат (см. GetApparent) вместо среднего наклона эклиптики нужен истинный:
+// c.GetOriented(GetTrueObliquity(epoch)).
+func (c SphericalCoords) GetEcliptic(epoch Epoch) SphericalCoords {
+	return c.GetOriented(GetMeanObliquity(epoch))
+}
+
+// GetEquatorialFromEcliptic возвращает экваториальные координаты для эклиптических координат,
+// отнесённых к средней эклиптике и равноденствию эпохи epoch; обратное преобразование к GetEcliptic.
+func (c SphericalCoords) GetEquatorialFromEcliptic(epoch Epoch) SphericalCoords {
+	return c.GetOriented(-GetMeanObliquity(epoch))
 }
 
 const (
@@ -0,0 +1,257 @@
+package gorewind
+
+// gorewind. Golang library for working with astronomical and geographical objects in spherical coordinates system.
+// Библиотека для работы с астрономическими и географическими объектами в сферической системе координат.
+// Copyright © 2021 Dvoeglazyi
+// License: http://github.com/dvoeglazyi/gorewind/LICENSE
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Шкалы времени связаны соотношениями TT = TAI + 32.184 с, TAI = UTC + ΔAT (високосные секунды), TT = UT1 + ΔT.
+// До 1972 года ΔAT менялась непрерывно, а до 1960 года UTC не существовало: время до 1960 года считается всемирным UT1.
+// ΔT до 1972 года и после окончания таблицы високосных секунд вычисляется по полиномам F. Espenak и J. Meeus,
+// Five Millennium Canon of Solar Eclipses (NASA/TP-2006-214141); разница UT1 − UTC (меньше 0.9 с) не учитывается.
+
+// TimeScale шкала времени.
+type TimeScale int
+
+const (
+	TT  TimeScale = iota // земное время, шкала эпох Epoch
+	TAI                  // международное атомное время
+	UTC                  // всемирное координированное время, шкала time.Time
+	UT1                  // всемирное время, определяемое вращением Земли
+	TDB                  // барицентрическое динамическое время, шкала эфемерид
+)
+
+var timeScaleNames = [...]string{
+	TT:  "TT",
+	TAI: "TAI",
+	UTC: "UTC",
+	UT1: "UT1",
+	TDB: "TDB",
+}
+
+// String возвращает сокращённое название шкалы времени.
+func (s TimeScale) String() string {
+	if s < 0 || int(s) >= len(timeScaleNames) {
+		return "TimeScale(" + strconv.Itoa(int(s)) + ")"
+	}
+	return timeScaleNames[s]
+}
+
+const (
+	// ModifiedJulianDateOffset разница между юлианской и модифицированной юлианской датой.
+	ModifiedJulianDateOffset = 2400000.5
+	// ttMinusTAI разница TT − TAI в секундах.
+	ttMinusTAI = 32.184
+	// secondsPerDay количество секунд в сутках.
+	secondsPerDay = 86400
+)
+
+// leapSecond изменение ΔAT = TAI − UTC с начала месяца; до 1972 года ΔAT = offset + (MJD − epoch)·rate.
+type leapSecond struct {
+	year   int
+	month  time.Month
+	offset float64
+	epoch  float64
+	rate   float64
+}
+
+// leapSeconds таблица ΔAT по бюллетеням IERS C, как в функции iauDat библиотеки SOFA.
+var leapSeconds = [...]leapSecond{
+	{1960, time.January, 1.4178180, 37300, 0.0012960},
+	{1961, time.January, 1.4228180, 37300, 0.0012960},
+	{1961, time.August, 1.3728180, 37300, 0.0012960},
+	{1962, time.January, 1.8458580, 37665, 0.0011232},
+	{1963, time.November, 1.9458580, 37665, 0.0011232},
+	{1964, time.January, 3.2401300, 38761, 0.0012960},
+	{1964, time.April, 3.3401300, 38761, 0.0012960},
+	{1964, time.September, 3.4401300, 38761, 0.0012960},
+	{1965, time.January, 3.5401300, 38761, 0.0012960},
+	{1965, time.March, 3.6401300, 38761, 0.0012960},
+	{1965, time.July, 3.7401300, 38761, 0.0012960},
+	{1965, time.September, 3.8401300, 38761, 0.0012960},
+	{1966, time.January, 4.3131700, 39126, 0.0025920},
+	{1968, time.February, 4.2131700, 39126, 0.0025920},
+	{1972, time.January, 10, 0, 0},
+	{1972, time.July, 11, 0, 0},
+	{1973, time.January, 12, 0, 0},
+	{1974, time.January, 13, 0, 0},
+	{1975, time.January, 14, 0, 0},
+	{1976, time.January, 15, 0, 0},
+	{1977, time.January, 16, 0, 0},
+	{1978, time.January, 17, 0, 0},
+	{1979, time.January, 18, 0, 0},
+	{1980, time.January, 19, 0, 0},
+	{1981, time.July, 20, 0, 0},
+	{1982, time.July, 21, 0, 0},
+	{1983, time.July, 22, 0, 0},
+	{1985, time.July, 23, 0, 0},
+	{1988, time.January, 24, 0, 0},
+	{1990, time.January, 25, 0, 0},
+	{1991, time.January, 26, 0, 0},
+	{1992, time.July, 27, 0, 0},
+	{1993, time.July, 28, 0, 0},
+	{1994, time.July, 29, 0, 0},
+	{1996, time.January, 30, 0, 0},
+	{1997, time.July, 31, 0, 0},
+	{1999, time.January, 32, 0, 0},
+	{2006, time.January, 33, 0, 0},
+	{2009, time.January, 34, 0, 0},
+	{2012, time.July, 35, 0, 0},
+	{2015, time.July, 36, 0, 0},
+	{2017, time.January, 37, 0, 0},
+}
+
+// leapSecondsExpiry год, до начала которого известно, что новых високосных секунд не будет.
+const leapSecondsExpiry = 2027
+
+// GetLeapSeconds возвращает разницу TAI − UTC в секундах на момент t; до 1960 года — 0.
+func GetLeapSeconds(t time.Time) float64 {
+	t = t.UTC()
+	for i := len(leapSeconds) - 1; i >= 0; i-- {
+		leap := &leapSeconds[i]
+		if t.Before(time.Date(leap.year, leap.month, 1, 0, 0, 0, 0, time.UTC)) {
+			continue
+		}
+		if leap.rate == 0 {
+			return leap.offset
+		}
+		return leap.offset + (getJulianDate(t)-ModifiedJulianDateOffset-leap.epoch)*leap.rate
+	}
+	return 0
+}
+
+// GetDeltaT возвращает разницу ΔT = TT − UT1 в секундах на эпоху epoch.
+func GetDeltaT(epoch Epoch) float64 {
+	year := epoch.JulianYear()
+	switch {
+	case year < 1972:
+		return getDeltaTPolynomial(year)
+	case year < leapSecondsExpiry:
+		return ttMinusTAI + GetLeapSeconds(epoch.Time())
+	default:
+		// продолжение полинома от последнего известного значения
+		last := ttMinusTAI + leapSeconds[len(leapSeconds)-1].offset
+		return last + getDeltaTPolynomial(year) - getDeltaTPolynomial(leapSecondsExpiry)
+	}
+}
+
+// getDeltaTPolynomial возвращает ΔT в секундах для года year по полиномам Espenak и Meeus.
+func getDeltaTPolynomial(year float64) float64 {
+	switch {
+	case year < -500:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u
+	case year < 500:
+		u := year / 100
+		return 10583.6 + (-1014.41+(33.78311+(-5.952053+(-0.1798452+(0.022174192+0.0090316521*u)*u)*u)*u)*u)*u
+	case year < 1600:
+		u := (year - 1000) / 100
+		return 1574.2 + (-556.01+(71.23472+(0.319781+(-0.8503463+(-0.005050998+0.0083572073*u)*u)*u)*u)*u)*u
+	case year < 1700:
+		t := year - 1600
+		return 120 + (-0.9808+(-0.01532+t/7129)*t)*t
+	case year < 1800:
+		t := year - 1700
+		return 8.83 + (0.1603+(-0.0059285+(0.00013336-t/1174000)*t)*t)*t
+	case year < 1860:
+		t := year - 1800
+		return 13.72 + (-0.332447+(0.0068612+(0.0041116+(-0.00037436+(0.0000121272+(-0.0000001699+0.000000000875*t)*t)*t)*t)*t)*t)*t
+	case year < 1900:
+		t := year - 1860
+		return 7.62 + (0.5737+(-0.251754+(0.01680668+(-0.0004473624+t/233174)*t)*t)*t)*t
+	case year < 1920:
+		t := year - 1900
+		return -2.79 + (1.494119+(-0.0598939+(0.0061966-0.000197*t)*t)*t)*t
+	case year < 1941:
+		t := year - 1920
+		return 21.20 + (0.84493+(-0.076100+0.0020936*t)*t)*t
+	case year < 1961:
+		t := year - 1950
+		return 29.07 + (0.407+(-1.0/233+t/2547)*t)*t
+	case year < 1986:
+		t := year - 1975
+		return 45.45 + (1.067+(-1.0/260-t/718)*t)*t
+	case year < 2005:
+		t := year - 2000
+		return 63.86 + (0.3345+(-0.060374+(0.0017275+(0.000651814+0.00002373599*t)*t)*t)*t)*t
+	case year < 2050:
+		t := year - 2000
+		return 62.92 + (0.32217+0.005589*t)*t
+	case year < 2150:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-year)
+	default:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u
+	}
+}
+
+// getTDBMinusTT возвращает разницу TDB − TT в секундах (периодические члены амплитудой до 1.7 мс).
+func getTDBMinusTT(epoch Epoch) float64 {
+	g := (357.53 + 0.98560028*float64(epoch-J2000)) * Degree
+	return 0.001657*math.Sin(g) + 0.000014*math.Sin(2*g)
+}
+
+// getTTMinusUTC возвращает разницу TT − UTC в секундах на момент t; до 1960 года — ΔT.
+func getTTMinusUTC(t time.Time) float64 {
+	if t.Year() < leapSeconds[0].year {
+		return GetDeltaT(Epoch(getJulianDate(t)))
+	}
+	return ttMinusTAI + GetLeapSeconds(t)
+}
+
+// getJulianDate возвращает юлианскую дату момента t без смены шкалы времени.
+func getJulianDate(t time.Time) float64 {
+	return unixEpoch + (float64(t.Unix())+float64(t.Nanosecond())/1e9)/secondsPerDay
+}
+
+// getTime возвращает момент времени для юлианской даты julianDate без смены шкалы времени.
+func getTime(julianDate float64) time.Time {
+	days := julianDate - unixEpoch
+	seconds := math.Floor(days * secondsPerDay)
+	nanoseconds := math.Round((days*secondsPerDay - seconds) * 1e9)
+	return time.Unix(int64(seconds), int64(nanoseconds)).UTC()
+}
+
+// NewEpochFromJulianDate создаёт эпоху для юлианской даты julianDate, заданной в шкале времени scale.
+func NewEpochFromJulianDate(julianDate float64, scale TimeScale) Epoch {
+	switch scale {
+	case TAI:
+		return Epoch(julianDate + ttMinusTAI/secondsPerDay)
+	case UTC:
+		return NewEpoch(getTime(julianDate))
+	case UT1:
+		return Epoch(julianDate + GetDeltaT(Epoch(julianDate))/secondsPerDay)
+	case TDB:
+		return Epoch(julianDate - getTDBMinusTT(Epoch(julianDate))/secondsPerDay)
+	default:
+		return Epoch(julianDate)
+	}
+}
+
+// JulianDateIn возвращает юлианскую дату эпохи в шкале времени scale.
+func (e Epoch) JulianDateIn(scale TimeScale) float64 {
+	switch scale {
+	case TAI:
+		return float64(e) - ttMinusTAI/secondsPerDay
+	case UTC:
+		return getJulianDate(e.Time())
+	case UT1:
+		return float64(e) - GetDeltaT(e)/secondsPerDay
+	case TDB:
+		return float64(e) + getTDBMinusTT(e)/secondsPerDay
+	default:
+		return float64(e)
+	}
+}
+
+// ModifiedJulianDate возвращает модифицированную юлианскую дату эпохи в шкале TT.
+func (e Epoch) ModifiedJulianDate() float64 {
+	return float64(e) - ModifiedJulianDateOffset
+}
@@ -0,0 +1,64 @@
+package gorewind
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Эталоны для дат после 1972 года — значения iauDat из тестов t_sofa_c и границы таблицы.
+func TestGetLeapSeconds(t *testing.T) {
+	tests := []struct {
+		time time.Time
+		want float64
+	}{
+		{time.Date(1965, 6, 1, 0, 0, 0, 0, time.UTC), 3.835826},
+		{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(2003, 6, 1, 0, 0, 0, 0, time.UTC), 32},
+		{time.Date(2008, 1, 17, 0, 0, 0, 0, time.UTC), 33},
+		{time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC), 36},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+		{time.Date(2017, 9, 1, 0, 0, 0, 0, time.UTC), 37},
+	}
+	for _, test := range tests {
+		if leapSeconds := GetLeapSeconds(test.time); math.Abs(leapSeconds-test.want) > 1e-9 {
+			t.Errorf("%v: %v, want %v", test.time, leapSeconds, test.want)
+		}
+	}
+}
+
+// Секунда координации 2016-12-31T23:59:60 не представима в time.Time, но между соседними секундами
+// по шкале TT проходит 2 с. Точность эпохи (юлианской даты в float64) — около 40 мкс.
+func TestNewEpochLeapSecond(t *testing.T) {
+	before := time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC)
+	after := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	if seconds := float64(NewEpoch(after)-NewEpoch(before)) * secondsPerDay; math.Abs(seconds-2) > 1e-4 {
+		t.Errorf("TT interval %v s, want 2 s", seconds)
+	}
+	for _, moment := range []time.Time{before, after} {
+		if got := NewEpoch(moment).Time(); math.Abs(got.Sub(moment).Seconds()) > 1e-4 {
+			t.Errorf("round trip %v, want %v", got, moment)
+		}
+	}
+}
+
+func TestGetDeltaT(t *testing.T) {
+	tests := []struct {
+		year      float64
+		want      float64
+		tolerance float64
+	}{
+		{1600, 120, 1e-9},
+		{1900, -2.79, 1e-9},
+		{2000, 64.184, 1e-9},
+		{2017.5, 69.184, 1e-9},
+		// на границах таблицы секунд координации ΔT непрерывна
+		{1971.99, 42.184, 0.1},
+		{2027.01, 69.184, 0.01},
+	}
+	for _, test := range tests {
+		if deltaT := GetDeltaT(NewJulianEpoch(test.year)); math.Abs(deltaT-test.want) > test.tolerance {
+			t.Errorf("%v: %v, want %v", test.year, deltaT, test.want)
+		}
+	}
+}